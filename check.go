@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/check"
+	"github.com/er1c-zh/sql-to-gorm/convert"
+)
+
+// checkModels reports the differences between the sql schema and the go models,
+// exits with 1 if there are any, e.g. to fail CI.
+func checkModels(args []string) {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	path := flags.String("file", "", "path to sql file, same as a positional argument")
+	models := flags.String("models", ".", "directory of the go models")
+	jobs := flags.Int("j", 0, "number of statements parsed concurrently, 0 for the number of CPUs")
+	dialect := flags.String("dialect", convert.DialectMySQL, "dialect of the sql: "+strings.Join(convert.Dialects, ", "))
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(),
+			"Usage: %s check [flags] [file|dir|glob|-]...\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	inputs := flags.Args()
+	if *path != "" {
+		inputs = append([]string{*path}, inputs...)
+	}
+	if len(inputs) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	inputs, err := ExpandInputs(inputs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(2)
+	}
+	option := convert.DefaultOption()
+	option.Jobs = *jobs
+	option.Dialect = *dialect
+	parsed := parseInputs(inputs, "", nil, option)
+
+	mismatches, err := check.Dir(parsed, *models)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read models fail: %s\n", err.Error())
+		os.Exit(2)
+	}
+	for _, m := range mismatches {
+		fmt.Println(m.String())
+	}
+	if len(mismatches) > 0 {
+		fmt.Fprintf(os.Stderr, "%d mismatches between the schema and the models\n", len(mismatches))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,332 @@
+// Package check compares go models with the parsed sql schema, to find
+// models which were not regenerated after a schema change or were edited by hand.
+//
+// Models are read with go/parser only, so the model package need not compile.
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/reverse"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// Mismatch is a difference between a table and its model.
+type Mismatch struct {
+	// Pos is the file:line of the model or field, empty for a missing model.
+	Pos    string
+	Table  string
+	Column string
+	Msg    string
+}
+
+func (m Mismatch) String() string {
+	name := m.Table
+	if m.Column != "" {
+		name += "." + m.Column
+	}
+	if m.Pos == "" {
+		return fmt.Sprintf("%s: %s", name, m.Msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", m.Pos, name, m.Msg)
+}
+
+// model is a struct read from the go files.
+type model struct {
+	name   string
+	pos    token.Position
+	fields []*field
+}
+
+type field struct {
+	name string
+	// goType is the type as written, e.g. time.Time
+	goType string
+	// tag is the gorm tag, settings are its parsed settings
+	tag      string
+	settings map[string]string
+	pos      token.Position
+}
+
+// column returns the column name of f, the column setting or the snake case field name.
+func (f *field) column() string {
+	if name := f.settings["COLUMN"]; name != "" {
+		return name
+	}
+	return convert.CamelToSnake(f.name)
+}
+
+// Dir compares the tables of s with the models declared in the go files of dir.
+func Dir(s *schema.Schema, dir string) ([]Mismatch, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(paths))
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return Files(s, fset, files), nil
+}
+
+// Files compares the tables of s with the models declared in files.
+// A table matches the struct whose TableName method returns its name,
+// or the struct named after it like the generated one, e.g. UserInfo for user_info,
+// see convert.StructNames.
+func Files(s *schema.Schema, fset *token.FileSet, files []*ast.File) []Mismatch {
+	models := readModels(fset, files)
+	byTable := map[string]*model{}
+	for name, table := range reverse.TableNames(files) {
+		if m, ok := models[name]; ok {
+			byTable[strings.ToLower(table)] = m
+		}
+	}
+
+	structNames := convert.StructNames(s)
+	result := make([]Mismatch, 0)
+	for _, t := range s.Tables {
+		m, ok := byTable[strings.ToLower(t.Name)]
+		if !ok {
+			m, ok = models[structNames[t]]
+		}
+		if !ok {
+			result = append(result, Mismatch{
+				Table: t.Name,
+				Msg:   fmt.Sprintf("missing model %s", structNames[t]),
+			})
+			continue
+		}
+		result = append(result, compare(t, m)...)
+	}
+	return result
+}
+
+// compare compares the columns and indexes of t with the fields of m.
+func compare(t *schema.Table, m *model) []Mismatch {
+	result := make([]Mismatch, 0)
+	report := func(pos token.Position, column, format string, args ...interface{}) {
+		result = append(result, Mismatch{
+			Pos:    pos.String(),
+			Table:  t.Name,
+			Column: column,
+			Msg:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	fields := map[string]*field{}
+	byName := map[string]*field{}
+	for _, f := range m.fields {
+		fields[strings.ToLower(f.column())] = f
+		byName[f.name] = f
+	}
+	for _, col := range t.Columns {
+		f, ok := fields[strings.ToLower(col.Name)]
+		if !ok {
+			// the field named after the column is tagged with another column
+			if f, ok = byName[convert.GoName(col.Name)]; !ok {
+				report(m.pos, col.Name, "missing field %s", convert.GoName(col.Name))
+				continue
+			}
+			report(f.pos, col.Name, "field %s has tag column:%s, want column:%s", f.name, f.column(), col.Name)
+		}
+		delete(fields, strings.ToLower(f.column()))
+		// a pointer is allowed for nullable columns
+		if want := convert.LookupType(col.Type).Name; strings.TrimPrefix(f.goType, "*") != want {
+			report(f.pos, col.Name, "field %s has type %s, want %s", f.name, f.goType, want)
+		}
+		value, ok := f.settings["DEFAULT"]
+		switch {
+		case col.Default != nil && (!ok || value != *col.Default):
+			report(f.pos, col.Name, "field %s has tag default:%s, want default:%s", f.name, value, *col.Default)
+		case col.Default == nil && ok:
+			report(f.pos, col.Name, "field %s has tag default:%s, but the column has no default", f.name, value)
+		}
+	}
+	for _, f := range m.fields {
+		if _, ok := fields[strings.ToLower(f.column())]; ok {
+			report(f.pos, f.column(), "field %s has no column", f.name)
+		}
+	}
+
+	// the primary key and unique keys are always checked, other indexes
+	// only for models which declare indexes, hand-written models often do not.
+	indexes := modelIndexes(t.Name, m)
+	declared := false
+	for _, idx := range indexes {
+		declared = declared || idx.Kind != schema.IndexPrimary && idx.Kind != schema.IndexUnique
+	}
+	for _, idx := range t.Indexes {
+		if idx.Functional() {
+			// gorm tags cannot declare expressions
+			continue
+		}
+		columns := strings.Join(idx.ColumnNames(), ",")
+		found := -1
+		for i, declared := range indexes {
+			if !strings.EqualFold(strings.Join(declared.ColumnNames(), ","), columns) {
+				continue
+			}
+			if found < 0 || declared.Kind == idx.Kind {
+				found = i
+			}
+		}
+		var kind string
+		if found >= 0 {
+			kind = indexes[found].Kind
+			indexes = append(indexes[:found], indexes[found+1:]...)
+		}
+		if !declared && idx.Kind != schema.IndexPrimary && idx.Kind != schema.IndexUnique {
+			continue
+		}
+		name := idx.Name
+		if idx.Kind == schema.IndexPrimary {
+			name = "PRIMARY"
+		}
+		switch {
+		case found < 0:
+			report(m.pos, "", "missing index %s (%s)", name, columns)
+		case kind != idx.Kind:
+			report(m.pos, "", "index %s (%s) is %s, want %s", name, columns, kind, idx.Kind)
+		}
+	}
+	for _, idx := range indexes {
+		report(m.pos, "", "index %s (%s) is not in the table", idx.Name, strings.Join(idx.ColumnNames(), ","))
+	}
+	return result
+}
+
+// modelIndexes returns the indexes declared by the tags of m, in the order of the fields.
+// Like gorm, a field named ID is the primary key if no field is tagged primaryKey.
+func modelIndexes(table string, m *model) []*schema.Index {
+	result := make([]*schema.Index, 0)
+	priorities := map[*schema.IndexColumn]int{}
+	add := func(tag reverse.IndexTag) {
+		var idx *schema.Index
+		for _, declared := range result {
+			if declared.Name == tag.Name {
+				idx = declared
+			}
+		}
+		if idx == nil {
+			idx = &schema.Index{Name: tag.Name, Kind: tag.Kind}
+			result = append(result, idx)
+		}
+		priorities[tag.Column] = tag.Priority
+		idx.Columns = append(idx.Columns, tag.Column)
+		sort.SliceStable(idx.Columns, func(i, j int) bool {
+			return priorities[idx.Columns[i]] < priorities[idx.Columns[j]]
+		})
+	}
+	var id *field
+	hasPrimaryKey := false
+	for _, f := range m.fields {
+		column := f.column()
+		if _, ok := f.settings["PRIMARYKEY"]; ok {
+			hasPrimaryKey = true
+			add(reverse.IndexTag{Kind: schema.IndexPrimary, Name: "PRIMARY", Column: &schema.IndexColumn{Name: column}})
+		} else if f.name == "ID" && id == nil {
+			id = f
+		}
+		for _, tag := range reverse.IndexTags(f.tag, table, column) {
+			add(tag)
+		}
+	}
+	if !hasPrimaryKey && id != nil {
+		pk := &schema.Index{Kind: schema.IndexPrimary, Name: "PRIMARY", Columns: []*schema.IndexColumn{{Name: id.column()}}}
+		result = append([]*schema.Index{pk}, result...)
+	}
+	return result
+}
+
+// readModels returns the structs of files by name,
+// fields of structs embedded from the same files are flattened.
+func readModels(fset *token.FileSet, files []*ast.File) map[string]*model {
+	structs := map[string]*ast.TypeSpec{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if _, ok := typeSpec.Type.(*ast.StructType); ok {
+					structs[typeSpec.Name.Name] = typeSpec
+				}
+			}
+		}
+	}
+
+	var fieldsOf func(st *ast.StructType, prefix string, seen map[string]bool) []*field
+	fieldsOf = func(st *ast.StructType, prefix string, seen map[string]bool) []*field {
+		result := make([]*field, 0)
+		for _, f := range st.Fields.List {
+			var gormTag string
+			if f.Tag != nil {
+				tag, _ := strconv.Unquote(f.Tag.Value)
+				gormTag, _ = reflect.StructTag(tag).Lookup("gorm")
+			}
+			settings := reverse.ParseTag(gormTag)
+			if _, ok := settings["-"]; ok {
+				continue
+			}
+			goType := types.ExprString(f.Type)
+			_, embedded := settings["EMBEDDED"]
+			if len(f.Names) == 0 || embedded {
+				name := strings.TrimPrefix(goType, "*")
+				if spec, ok := structs[name]; ok && !seen[name] {
+					seen[name] = true
+					result = append(result,
+						fieldsOf(spec.Type.(*ast.StructType), prefix+settings["EMBEDDEDPREFIX"], seen)...)
+					delete(seen, name)
+				}
+				continue
+			}
+			for _, name := range f.Names {
+				if !name.IsExported() {
+					continue
+				}
+				fd := &field{
+					name:     name.Name,
+					goType:   goType,
+					tag:      gormTag,
+					settings: settings,
+					pos:      fset.Position(name.Pos()),
+				}
+				if prefix != "" && settings["COLUMN"] == "" {
+					settings["COLUMN"] = prefix + convert.CamelToSnake(name.Name)
+				}
+				result = append(result, fd)
+			}
+		}
+		return result
+	}
+
+	models := map[string]*model{}
+	for name, spec := range structs {
+		models[name] = &model{
+			name:   name,
+			pos:    fset.Position(spec.Pos()),
+			fields: fieldsOf(spec.Type.(*ast.StructType), "", map[string]bool{name: true}),
+		}
+	}
+	return models
+}
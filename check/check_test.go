@@ -0,0 +1,164 @@
+package check
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+const usersSQL = `CREATE TABLE users (
+	id bigint NOT NULL AUTO_INCREMENT,
+	email varchar(320) NOT NULL,
+	name varchar(64) NOT NULL,
+	age int NOT NULL DEFAULT 0,
+	PRIMARY KEY (id),
+	UNIQUE KEY uk_email (email),
+	KEY idx_name_age (name, age)
+);`
+
+func TestFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		model string
+		want  []string
+	}{{
+		name: "hand-written",
+		model: `type Users struct {
+			ID    int64
+			Email string ` + "`gorm:\"uniqueIndex:uk_email\"`" + `
+			Name  string
+			Age   int64 ` + "`gorm:\"default:0\"`" + `
+		}`,
+	}, {
+		name: "primary key drift",
+		model: `type Users struct {
+			ID    int64
+			Email string ` + "`gorm:\"primaryKey;uniqueIndex:uk_email\"`" + `
+			Name  string
+			Age   int64 ` + "`gorm:\"default:0\"`" + `
+		}`,
+		want: []string{
+			"missing index PRIMARY (id)",
+			"index PRIMARY (email) is not in the table",
+		},
+	}, {
+		name: "missing unique key",
+		model: `type Users struct {
+			ID    int64
+			Email string
+			Name  string
+			Age   int64 ` + "`gorm:\"default:0\"`" + `
+		}`,
+		want: []string{"missing index uk_email (email)"},
+	}, {
+		name: "index drift",
+		model: `type Users struct {
+			Id    int64  ` + "`gorm:\"primaryKey\"`" + `
+			Email string ` + "`gorm:\"index:uk_email\"`" + `
+			Name  string ` + "`gorm:\"index:idx_name\"`" + `
+			Age   int64  ` + "`gorm:\"default:0\"`" + `
+		}`,
+		want: []string{
+			"index uk_email (email) is INDEX, want UNIQUE",
+			"missing index idx_name_age (name,age)",
+			"index idx_name (name) is not in the table",
+		},
+	}, {
+		name: "composite order",
+		model: `type Users struct {
+			Id    int64  ` + "`gorm:\"primaryKey\"`" + `
+			Email string ` + "`gorm:\"uniqueIndex:uk_email\"`" + `
+			Name  string ` + "`gorm:\"index:idx_name_age,priority:2\"`" + `
+			Age   int64  ` + "`gorm:\"default:0;index:idx_name_age,priority:1\"`" + `
+		}`,
+		want: []string{
+			"missing index idx_name_age (name,age)",
+			"index idx_name_age (age,name) is not in the table",
+		},
+	}}
+	s := parse(t, usersSQL)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, file := parseModels(t, "package models\n\n"+tt.model)
+			if got := messages(Files(s, fset, []*ast.File{file})); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Files =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+// TestGenerated checks that the generated models match their schema, indexes included.
+func TestGenerated(t *testing.T) {
+	s := parse(t, usersSQL+`CREATE TABLE tags (
+		user_id bigint NOT NULL,
+		tag varchar(32) NOT NULL,
+		note text,
+		PRIMARY KEY (user_id, tag),
+		FULLTEXT KEY ft_note (note)
+	);`)
+	buf := new(bytes.Buffer)
+	if err := convert.Render(s, buf, convert.DefaultOption()); err != nil {
+		t.Fatal(err)
+	}
+	fset, file := parseModels(t, buf.String())
+	if got := messages(Files(s, fset, []*ast.File{file})); len(got) != 0 {
+		t.Errorf("Files = %v, want no mismatches", got)
+	}
+
+	// the schema gained an index the models lack
+	s.Tables[0].Indexes = append(s.Tables[0].Indexes, &schema.Index{
+		Name: "uk_name", Kind: schema.IndexUnique, Columns: []*schema.IndexColumn{{Name: "name"}},
+	})
+	want := []string{"missing index uk_name (name)"}
+	if got := messages(Files(s, fset, []*ast.File{file})); !reflect.DeepEqual(got, want) {
+		t.Errorf("Files = %v, want %v", got, want)
+	}
+}
+
+// TestDatabases checks the models generated for tables of the same name in two databases,
+// the second one is Users2.
+func TestDatabases(t *testing.T) {
+	s := parse(t, "USE shop;"+usersSQL+"USE audit;CREATE TABLE users (id bigint NOT NULL, at datetime, PRIMARY KEY (id));")
+	buf := new(bytes.Buffer)
+	if err := convert.Render(s, buf, convert.DefaultOption()); err != nil {
+		t.Fatal(err)
+	}
+	fset, file := parseModels(t, buf.String())
+	if got := messages(Files(s, fset, []*ast.File{file})); len(got) != 0 {
+		t.Errorf("Files = %v, want no mismatches", got)
+	}
+}
+
+func parse(t *testing.T, sql string) *schema.Schema {
+	t.Helper()
+	s, err := convert.Convert(strings.NewReader(sql), convert.DefaultOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func parseModels(t *testing.T, src string) (*token.FileSet, *ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "models.go", src, parser.SkipObjectResolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fset, file
+}
+
+func messages(mismatches []Mismatch) []string {
+	var result []string
+	for _, m := range mismatches {
+		result = append(result, m.Msg)
+	}
+	return result
+}
@@ -0,0 +1,475 @@
+package convert
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	gen "github.com/er1c-zh/sql-to-gorm/antlr4_gen"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// ALTER TABLE and CREATE/DROP INDEX are applied to the tables parsed so far,
+// so replaying a migration history produces the final schema.
+
+func (l *Listener) EnterAlterTable(ctx *gen.AlterTableContext) {
+	if l.CurrentTable != nil {
+		l.Error(ctx, "table %s is not done", l.CurrentTable.Name)
+	}
+	l.CurrentTable = l.lookupTable(ctx, ctx.TableName())
+}
+func (l *Listener) ExitAlterTable(ctx *gen.AlterTableContext) {
+	if l.CurrentTable != nil {
+		l.alterRewritten(ctx)
+	}
+	l.CurrentTable = nil
+	l.CurrentCol = nil
+}
+
+// alterRewritten applies the MySQL 8.0 specifications rewritten out of an ALTER TABLE,
+// see rewrite8, after the others in the order they are written.
+func (l *Listener) alterRewritten(ctx *gen.AlterTableContext) {
+	var rewrites []rewrite
+	for _, kind := range []string{rewriteSetVisible, rewriteSetInvisible, rewriteAlterEnforced, rewriteAlterNotEnforced} {
+		rewrites = append(rewrites, l.rewritten(ctx, kind)...)
+	}
+	sort.Slice(rewrites, func(i, j int) bool { return rewrites[i].offset < rewrites[j].offset })
+	for _, r := range rewrites {
+		switch r.kind {
+		case rewriteSetVisible, rewriteSetInvisible:
+			c := l.CurrentTable.Column(r.value)
+			if c == nil {
+				l.Error(ctx, "unknown column %s in table %s", r.value, l.CurrentTable.Name)
+				continue
+			}
+			c.Invisible = r.kind == rewriteSetInvisible
+		default:
+			c := l.CurrentTable.Constraint(r.value)
+			if c == nil || c.Type != schema.ConstraintCheck {
+				l.Error(ctx, "unknown check constraint %s in table %s", r.value, l.CurrentTable.Name)
+				continue
+			}
+			c.NotEnforced = r.kind == rewriteAlterNotEnforced
+		}
+	}
+}
+
+// lookupTable returns the parsed table named by ctx, warns if there is none.
+func (l *Listener) lookupTable(ctx *gen.AlterTableContext, name gen.ITableNameContext) *schema.Table {
+	database, table := l.tableName(name)
+	t := l.Schema.Lookup(database, table)
+	if t == nil {
+		l.option.warnf("%s:%d: alter unknown table %s, skipped",
+			l.CurrentSource, ctx.GetStart().GetLine(), table)
+	}
+	return t
+}
+
+/////////////////////////////////////////////
+// column ///////////////////////////////////
+/////////////////////////////////////////////
+
+func (l *Listener) EnterAlterByAddColumn(ctx *gen.AlterByAddColumnContext) {
+	l.startColumn(uid(ctx.Uid(0)))
+}
+func (l *Listener) ExitAlterByAddColumn(ctx *gen.AlterByAddColumnContext) {
+	if l.CurrentTable == nil || l.CurrentCol == nil {
+		return
+	}
+	if l.CurrentTable.Column(l.CurrentCol.Name) != nil {
+		l.Error(ctx, "add column %s: already exists in table %s", l.CurrentCol.Name, l.CurrentTable.Name)
+	} else {
+		l.CurrentTable.AddColumn(l.CurrentCol, ctx.FIRST() != nil, uid(ctx.Uid(1)))
+		l.addColumnIndex(l.CurrentCol)
+	}
+	l.CurrentCol = nil
+}
+
+// ALTER TABLE t ADD (a int, b int), the name of each definition is the uid before it.
+func (l *Listener) EnterColumnDefinition(ctx *gen.ColumnDefinitionContext) {
+	parent, ok := ctx.GetParent().(*gen.AlterByAddColumnsContext)
+	if !ok {
+		return
+	}
+	for i, def := range parent.AllColumnDefinition() {
+		if def == ctx {
+			l.startColumn(uid(parent.Uid(i)))
+			return
+		}
+	}
+}
+func (l *Listener) ExitColumnDefinition(ctx *gen.ColumnDefinitionContext) {
+	l.setColumnAttributes(ctx)
+	if _, ok := ctx.GetParent().(*gen.AlterByAddColumnsContext); !ok {
+		return
+	}
+	if l.CurrentTable == nil || l.CurrentCol == nil {
+		return
+	}
+	if l.CurrentTable.Column(l.CurrentCol.Name) != nil {
+		l.Error(ctx, "add column %s: already exists in table %s", l.CurrentCol.Name, l.CurrentTable.Name)
+	} else {
+		l.CurrentTable.AddColumn(l.CurrentCol, false, "")
+		l.addColumnIndex(l.CurrentCol)
+	}
+	l.CurrentCol = nil
+}
+
+func (l *Listener) EnterAlterByModifyColumn(ctx *gen.AlterByModifyColumnContext) {
+	l.startColumn(uid(ctx.Uid(0)))
+}
+func (l *Listener) ExitAlterByModifyColumn(ctx *gen.AlterByModifyColumnContext) {
+	l.replaceColumn(ctx, l.CurrentColName(), ctx.FIRST() != nil, uid(ctx.Uid(1)))
+}
+
+func (l *Listener) EnterAlterByChangeColumn(ctx *gen.AlterByChangeColumnContext) {
+	l.startColumn(uid(ctx.GetNewColumn()))
+}
+func (l *Listener) ExitAlterByChangeColumn(ctx *gen.AlterByChangeColumnContext) {
+	l.replaceColumn(ctx, uid(ctx.GetOldColumn()), ctx.FIRST() != nil, uid(ctx.GetAfterColumn()))
+}
+
+// CurrentColName returns the name of the current column, empty if there is none.
+func (l *Listener) CurrentColName() string {
+	if l.CurrentCol == nil {
+		return ""
+	}
+	return l.CurrentCol.Name
+}
+
+func (l *Listener) startColumn(name string) {
+	if l.CurrentTable == nil {
+		return
+	}
+	l.CurrentCol = &schema.Column{
+		Name:     name,
+		Nullable: true,
+	}
+}
+
+// replaceColumn replaces the column old with the current column,
+// keeping its position unless first or after is given.
+func (l *Listener) replaceColumn(ctx antlr.ParserRuleContext, old string, first bool, after string) {
+	defer func() {
+		l.CurrentCol = nil
+	}()
+	if l.CurrentTable == nil || l.CurrentCol == nil {
+		return
+	}
+	t := l.CurrentTable
+	pos := t.ColumnIndex(old)
+	if pos < 0 {
+		l.Error(ctx, "unknown column %s in table %s", old, t.Name)
+		return
+	}
+	if !strings.EqualFold(old, l.CurrentCol.Name) && t.Column(l.CurrentCol.Name) != nil {
+		l.Error(ctx, "change column %s to %s: already exists in table %s", old, l.CurrentCol.Name, t.Name)
+		return
+	}
+	t.RenameColumn(old, l.CurrentCol.Name)
+	// the primary key is kept, other attributes come from the new definition
+	if t.Columns[pos].PrimaryKey {
+		l.CurrentCol.PrimaryKey = true
+		l.CurrentCol.Nullable = false
+	}
+	if !first && after == "" {
+		t.Columns[pos] = l.CurrentCol
+	} else {
+		t.Columns = append(t.Columns[:pos], t.Columns[pos+1:]...)
+		t.AddColumn(l.CurrentCol, first, after)
+	}
+	l.addColumnIndex(l.CurrentCol)
+}
+
+func (l *Listener) EnterAlterByRenameColumn(ctx *gen.AlterByRenameColumnContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	from, to := uid(ctx.GetOldColumn()), uid(ctx.GetNewColumn())
+	if l.CurrentTable.Column(to) != nil {
+		l.Error(ctx, "rename column %s to %s: already exists in table %s", from, to, l.CurrentTable.Name)
+		return
+	}
+	if l.CurrentTable.RenameColumn(from, to) == nil {
+		l.Error(ctx, "unknown column %s in table %s", from, l.CurrentTable.Name)
+	}
+}
+
+func (l *Listener) EnterAlterByDropColumn(ctx *gen.AlterByDropColumnContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	name := uid(ctx.Uid())
+	if l.CurrentTable.DropColumn(name) == nil {
+		l.Error(ctx, "unknown column %s in table %s", name, l.CurrentTable.Name)
+	}
+}
+
+func (l *Listener) EnterAlterByChangeDefault(ctx *gen.AlterByChangeDefaultContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	col := l.CurrentTable.Column(uid(ctx.Uid()))
+	if col == nil {
+		l.Error(ctx, "unknown column %s in table %s", uid(ctx.Uid()), l.CurrentTable.Name)
+		return
+	}
+	if ctx.DROP() != nil {
+		col.Default = nil
+		return
+	}
+	value := originalText(ctx.DefaultValue())
+	col.Default = &value
+}
+
+/////////////////////////////////////////////
+// index and constraint /////////////////////
+/////////////////////////////////////////////
+
+func (l *Listener) EnterAlterByAddIndex(ctx *gen.AlterByAddIndexContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	l.addIndex(l.newIndex(schema.IndexNormal, ctx.Uid(), ctx.IndexType(), ctx.IndexColumnNames(), ctx.AllIndexOption()))
+}
+
+func (l *Listener) EnterAlterByAddPrimaryKey(ctx *gen.AlterByAddPrimaryKeyContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	if l.CurrentTable.PrimaryKey() != nil {
+		l.Error(ctx, "table %s already has a primary key", l.CurrentTable.Name)
+		return
+	}
+	idx := l.newIndex(schema.IndexPrimary, nil, ctx.IndexType(), ctx.IndexColumnNames(), ctx.AllIndexOption())
+	idx.Clustered = l.clustered(ctx)
+	l.addIndex(idx)
+	l.setPrimaryKey(idx.ColumnNames(), true)
+}
+
+func (l *Listener) EnterAlterByAddUniqueKey(ctx *gen.AlterByAddUniqueKeyContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	name := ctx.GetIndexName()
+	if name == nil {
+		name = ctx.GetName()
+	}
+	l.addIndex(l.newIndex(schema.IndexUnique, name, ctx.IndexType(), ctx.IndexColumnNames(), ctx.AllIndexOption()))
+}
+
+func (l *Listener) EnterAlterByAddSpecialIndex(ctx *gen.AlterByAddSpecialIndexContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	kind := schema.IndexFulltext
+	if ctx.SPATIAL() != nil {
+		kind = schema.IndexSpatial
+	}
+	l.addIndex(l.newIndex(kind, ctx.Uid(), nil, ctx.IndexColumnNames(), ctx.AllIndexOption()))
+}
+
+func (l *Listener) EnterAlterByAddForeignKey(ctx *gen.AlterByAddForeignKeyContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	name := ctx.GetName()
+	if name == nil {
+		name = ctx.GetIndexName()
+	}
+	fk := &schema.Constraint{
+		Name:    uid(name),
+		Type:    schema.ConstraintForeignKey,
+		Columns: l.newIndex("", nil, nil, ctx.IndexColumnNames(), nil).ColumnNames(),
+	}
+	l.setReference(fk, ctx.ReferenceDefinition())
+	l.CurrentTable.Constraints = append(l.CurrentTable.Constraints, fk)
+}
+
+func (l *Listener) EnterAlterByAddCheckTableConstraint(ctx *gen.AlterByAddCheckTableConstraintContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	l.CurrentTable.Constraints = append(l.CurrentTable.Constraints, &schema.Constraint{
+		Name:        uid(ctx.GetName()),
+		Type:        schema.ConstraintCheck,
+		Check:       originalText(ctx.Expression()),
+		NotEnforced: l.notEnforced(ctx),
+	})
+}
+
+func (l *Listener) EnterAlterByDropIndex(ctx *gen.AlterByDropIndexContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	l.dropIndex(ctx, uid(ctx.Uid()))
+}
+
+func (l *Listener) dropIndex(ctx antlr.ParserRuleContext, name string) {
+	if removeIndex(l.CurrentTable, name) == nil {
+		l.Error(ctx, "unknown index %s in table %s", name, l.CurrentTable.Name)
+	}
+}
+
+// removeIndex removes the index name of t, the column of a unique index is no longer unique.
+func removeIndex(t *schema.Table, name string) *schema.Index {
+	idx := t.DropIndex(name)
+	if idx != nil && idx.Kind == schema.IndexUnique && len(idx.Columns) == 1 {
+		if col := t.Column(idx.Columns[0].Name); col != nil {
+			col.Unique = false
+		}
+	}
+	return idx
+}
+
+func (l *Listener) EnterAlterByDropPrimaryKey(ctx *gen.AlterByDropPrimaryKeyContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	for i, idx := range l.CurrentTable.Indexes {
+		if idx.Kind == schema.IndexPrimary {
+			l.CurrentTable.Indexes = append(l.CurrentTable.Indexes[:i], l.CurrentTable.Indexes[i+1:]...)
+			l.setPrimaryKey(idx.ColumnNames(), false)
+			return
+		}
+	}
+	l.Error(ctx, "table %s has no primary key", l.CurrentTable.Name)
+}
+
+func (l *Listener) setPrimaryKey(columns []string, primaryKey bool) {
+	for _, name := range columns {
+		if col := l.CurrentTable.Column(name); col != nil {
+			col.PrimaryKey = primaryKey
+			if primaryKey {
+				col.Nullable = false
+			}
+		}
+	}
+}
+
+func (l *Listener) EnterAlterByRenameIndex(ctx *gen.AlterByRenameIndexContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	from, to := uid(ctx.Uid(0)), uid(ctx.Uid(1))
+	idx := l.CurrentTable.Index(from)
+	if idx == nil {
+		l.Error(ctx, "unknown index %s in table %s", from, l.CurrentTable.Name)
+		return
+	}
+	idx.Name = to
+}
+
+func (l *Listener) EnterAlterByDropForeignKey(ctx *gen.AlterByDropForeignKeyContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	name := uid(ctx.Uid())
+	if l.CurrentTable.DropConstraint(name) == nil {
+		l.Error(ctx, "unknown foreign key %s in table %s", name, l.CurrentTable.Name)
+	}
+}
+
+func (l *Listener) EnterAlterByDropConstraintCheck(ctx *gen.AlterByDropConstraintCheckContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	name := uid(ctx.Uid())
+	if l.CurrentTable.DropConstraint(name) == nil {
+		l.Error(ctx, "unknown constraint %s in table %s", name, l.CurrentTable.Name)
+	}
+}
+
+/////////////////////////////////////////////
+// table ////////////////////////////////////
+/////////////////////////////////////////////
+
+func (l *Listener) EnterAlterByRename(ctx *gen.AlterByRenameContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	var to string
+	if ctx.Uid() != nil {
+		to = uid(ctx.Uid())
+	} else {
+		_, to = tableName(ctx.FullId())
+	}
+	l.renameTable(ctx, l.CurrentTable, to)
+}
+
+func (l *Listener) renameTable(ctx antlr.ParserRuleContext, t *schema.Table, to string) {
+	if other := l.Schema.Lookup(t.Database, to); other != nil && other != t {
+		l.Error(ctx, "rename table %s to %s: already exists", t.Name, to)
+		return
+	}
+	t.Name = to
+}
+
+func (l *Listener) EnterAlterByTableOption(ctx *gen.AlterByTableOptionContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	for _, option := range ctx.AllTableOption() {
+		l.addTableOption(option)
+	}
+}
+
+func (l *Listener) EnterAlterByConvertCharset(ctx *gen.AlterByConvertCharsetContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	l.setCharset(ctx.CharsetName(), ctx.CollationName())
+}
+
+func (l *Listener) EnterAlterByDefaultCharset(ctx *gen.AlterByDefaultCharsetContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	l.setCharset(ctx.CharsetName(), ctx.CollationName())
+}
+
+func (l *Listener) setCharset(charset gen.ICharsetNameContext, collation gen.ICollationNameContext) {
+	l.CurrentTable.SetOption("CHARSET", unquote(charset.GetText()))
+	if collation != nil {
+		l.CurrentTable.SetOption("COLLATE", unquote(collation.GetText()))
+	}
+}
+
+/////////////////////////////////////////////
+// create and drop index ////////////////////
+/////////////////////////////////////////////
+
+func (l *Listener) EnterCreateIndex(ctx *gen.CreateIndexContext) {
+	database, name := l.tableName(ctx.TableName())
+	t := l.Schema.Lookup(database, name)
+	if t == nil {
+		l.option.warnf("%s:%d: create index on unknown table %s, skipped",
+			l.CurrentSource, ctx.GetStart().GetLine(), name)
+		return
+	}
+	kind := schema.IndexNormal
+	switch {
+	case ctx.UNIQUE() != nil:
+		kind = schema.IndexUnique
+	case ctx.FULLTEXT() != nil:
+		kind = schema.IndexFulltext
+	case ctx.SPATIAL() != nil:
+		kind = schema.IndexSpatial
+	}
+	l.CurrentTable = t
+	l.addIndex(l.newIndex(kind, ctx.Uid(), ctx.IndexType(), ctx.IndexColumnNames(), ctx.AllIndexOption()))
+	l.CurrentTable = nil
+}
+
+func (l *Listener) EnterDropIndex(ctx *gen.DropIndexContext) {
+	database, name := l.tableName(ctx.TableName())
+	t := l.Schema.Lookup(database, name)
+	if t == nil {
+		l.option.warnf("%s:%d: drop index on unknown table %s, skipped",
+			l.CurrentSource, ctx.GetStart().GetLine(), name)
+		return
+	}
+	l.CurrentTable = t
+	l.dropIndex(ctx, uid(ctx.Uid()))
+	l.CurrentTable = nil
+}
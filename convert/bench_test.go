@@ -0,0 +1,68 @@
+package convert_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+)
+
+// schemaSQL returns the create table statements of n tables like those of a real schema.
+func schemaSQL(n int) string {
+	buf := new(strings.Builder)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(buf, "CREATE TABLE `t%d` (\n"+
+			"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n"+
+			"  `name` varchar(64) NOT NULL DEFAULT '' COMMENT 'name',\n"+
+			"  `price` decimal(10,2) DEFAULT NULL,\n"+
+			"  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,\n"+
+			"  `ref_id` bigint unsigned DEFAULT NULL,\n"+
+			"  PRIMARY KEY (`id`),\n"+
+			"  UNIQUE KEY `uk_name` (`name`),\n"+
+			"  KEY `idx_ref` (`ref_id`),\n"+
+			"  CONSTRAINT `fk_%d` FOREIGN KEY (`ref_id`) REFERENCES `t0` (`id`) ON DELETE CASCADE\n"+
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='table %d';\n", i, i, i)
+	}
+	return buf.String()
+}
+
+// BenchmarkConvert parses and renders generated schemas,
+// run with go test ./convert -run '^$' -bench Convert.
+func BenchmarkConvert(b *testing.B) {
+	for _, n := range []int{10, 100, 1000, 5000} {
+		sql := schemaSQL(n)
+		b.Run(fmt.Sprintf("tables=%d", n), func(b *testing.B) {
+			b.SetBytes(int64(len(sql)))
+			for i := 0; i < b.N; i++ {
+				option := convert.DefaultOption()
+				option.Warnf = nil
+				s, err := convert.Convert(strings.NewReader(sql), option)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := convert.Render(s, io.Discard, option); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkConvertDump parses the tables of a mysqldump, most statements are skipped.
+func BenchmarkConvertDump(b *testing.B) {
+	content, err := os.ReadFile("testdata/mysqldump.sql")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(content)))
+	for i := 0; i < b.N; i++ {
+		option := convert.DefaultOption()
+		option.Warnf = nil
+		if _, err := convert.Convert(strings.NewReader(string(content)), option); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
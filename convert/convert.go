@@ -0,0 +1,124 @@
+// Package convert converts MySQL create table statements to gorm models.
+//
+//	schema, err := convert.Convert(strings.NewReader(sql), convert.DefaultOption())
+//	if err != nil {
+//		return err
+//	}
+//	return convert.Render(schema, os.Stdout, convert.DefaultOption())
+package convert
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+type Option struct {
+	// Package of the generated go file.
+	Package string
+	// Template is the path or glob of the template files to render,
+	// empty to use the default template.
+	Template string
+	// Warnf receives the warnings, nil to discard them.
+	Warnf func(format string, args ...interface{})
+	// Jobs is the number of statements parsed concurrently, 0 for GOMAXPROCS.
+	Jobs int
+	// Statements are the kinds of statements parsed, e.g. CREATE TABLE, see Statement.Kind,
+	// nil for DefaultStatements.
+	Statements []string
+	// Dialect is the server the DDL is written for, one of Dialects, empty for mysql.
+	Dialect string
+	// StructNames are the names of the models of the tables, nil for StructNames of the
+	// rendered schema. Set them to StructNames of the whole schema to render its tables
+	// one by one, e.g. into a file each, without two models of the same name.
+	StructNames map[*schema.Table]string
+}
+
+func DefaultOption() Option {
+	return Option{
+		Package: "models",
+		Warnf: func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, "[WARN] "+format+"\n", args...)
+		},
+	}
+}
+
+func (o Option) warnf(format string, args ...interface{}) {
+	if o.Warnf == nil {
+		return
+	}
+	o.Warnf(format, args...)
+}
+
+// Converter merges the tables of many inputs into one schema.
+type Converter struct {
+	ln *Listener
+}
+
+func NewConverter(opts Option) *Converter {
+	return &Converter{
+		ln: NewListener(opts),
+	}
+}
+
+// Add parses all statements read from r, source names the input in errors.
+func (c *Converter) Add(source string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return c.ln.Parse(source, string(b))
+}
+
+// AddDatabase is Add with database as the current database, as if the input began
+// with USE database, e.g. for the SHOW CREATE TABLE output of a table of database.
+func (c *Converter) AddDatabase(database, source string, r io.Reader) error {
+	c.ln.Database = database
+	return c.Add(source, r)
+}
+
+// Schema returns the tables added so far.
+func (c *Converter) Schema() *schema.Schema {
+	return c.ln.Schema
+}
+
+// Convert parses all statements read from r.
+func Convert(r io.Reader, opts Option) (*schema.Schema, error) {
+	c := NewConverter(opts)
+	if err := c.Add("<input>", r); err != nil {
+		return nil, err
+	}
+	return c.Schema(), nil
+}
+
+// CheckReferences warns about foreign keys referencing tables
+// or columns which are not in s.
+func CheckReferences(s *schema.Schema, opts Option) {
+	CheckReferencesIn(s, s, opts)
+}
+
+// CheckReferencesIn warns about foreign keys of the tables of s referencing tables
+// or columns which are not in all, e.g. s is a filtered all.
+func CheckReferencesIn(s, all *schema.Schema, opts Option) {
+	for _, t := range s.Tables {
+		for _, c := range t.Constraints {
+			if c.Type != schema.ConstraintForeignKey {
+				continue
+			}
+			ref := all.Table(c.RefTable)
+			if ref == nil {
+				opts.warnf("table %s: foreign key %s references unknown table %s",
+					t.Name, c.Name, c.RefTable)
+				continue
+			}
+			for _, col := range c.RefColumns {
+				if ref.Column(col) == nil {
+					opts.warnf("table %s: foreign key %s references unknown column %s.%s",
+						t.Name, c.Name, c.RefTable, col)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package convert_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/ddl"
+)
+
+// TestMySQLDump converts a mysqldump with data, triggers, routines, views and two databases,
+// the models are compared in TestGolden.
+func TestMySQLDump(t *testing.T) {
+	f, err := os.Open("testdata/mysqldump.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	option := convert.DefaultOption()
+	option.Warnf = func(format string, args ...interface{}) {
+		t.Errorf("unexpected warning: "+format, args...)
+	}
+	s, err := convert.Convert(f, option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	databases := map[string]string{"customer": "shop", "orders": "shop", "log": "audit"}
+	for _, table := range s.Tables {
+		if want := databases[table.Name]; table.Database != want {
+			t.Errorf("table %s: database %q, want %q", table.Name, table.Database, want)
+		}
+	}
+
+}
+
+// TestJobs converts the same inputs with one and many parsers,
+// the models and errors must not depend on the order statements are parsed in.
+func TestJobs(t *testing.T) {
+	content, err := os.ReadFile("testdata/sakila.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := "CREATE TABLE a (x int);\nCREATE TABLE b (y int,);\nALTER TABLE a ADD COLUMN z int;\nCREATE TABLE (c int);\n"
+	var want string
+	for _, jobs := range []int{1, 4} {
+		option := convert.DefaultOption()
+		option.Warnf = nil
+		option.Jobs = jobs
+		converter := convert.NewConverter(option)
+		if err := converter.Add("sakila.sql", bytes.NewReader(content)); err != nil {
+			t.Fatal(err)
+		}
+		err := converter.Add("broken.sql", strings.NewReader(broken))
+		if list, ok := err.(convert.ErrorList); !ok || len(list) != 2 {
+			t.Fatalf("jobs %d: errors %v, want 2", jobs, err)
+		}
+		if a := converter.Schema().Table("a"); a == nil || a.Column("z") == nil {
+			t.Errorf("jobs %d: statements after an error are not applied", jobs)
+		}
+
+		buf := new(bytes.Buffer)
+		if err := convert.Render(converter.Schema(), buf, option); err != nil {
+			t.Fatal(err)
+		}
+		got := buf.String() + err.Error()
+		if jobs == 1 {
+			want = got
+		} else if got != want {
+			t.Errorf("jobs %d: output differs from jobs 1", jobs)
+		}
+	}
+}
+
+// TestMySQL80 converts the MySQL 8.0 constructs of testdata/mysql80.sql
+// and converts the table written back by ddl again, it must not change.
+func TestMySQL80(t *testing.T) {
+	content, err := os.ReadFile("testdata/mysql80.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	option := convert.DefaultOption()
+	option.Warnf = nil
+	s, err := convert.Convert(bytes.NewReader(content), option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := s.Table("account")
+	if c := table.Column("password_hash"); !c.Invisible {
+		t.Errorf("column password_hash is not invisible")
+	}
+	if c := table.Column("nickname"); c.Invisible {
+		t.Errorf("column nickname is invisible")
+	}
+	if srid, _ := table.Column("home").Option("SRID"); srid != "4326" {
+		t.Errorf("column home: srid %q, want 4326", srid)
+	}
+	expressions := map[string]string{
+		"uk_email":         "lower(`email`)",
+		"idx_created":      "date(`created_at`)",
+		"functional_index": "`balance` * 100",
+		"idx_note":         "left(`note`, 10)",
+		"idx_nickname":     "upper(`nickname`)",
+	}
+	for name, want := range expressions {
+		idx := table.Index(name)
+		if idx == nil {
+			t.Errorf("missing index %s", name)
+			continue
+		}
+		last := idx.Columns[len(idx.Columns)-1]
+		if last.Expression != want || last.Name != "" {
+			t.Errorf("index %s: expression %q, name %q, want %q", name, last.Expression, last.Name, want)
+		}
+	}
+	notEnforced := map[string]bool{"": true, "chk_balance": false, "chk_age": true, "chk_nickname": true}
+	for _, c := range table.Constraints {
+		if want, ok := notEnforced[c.Name]; !ok || c.NotEnforced != want {
+			t.Errorf("check %q: not enforced %v", c.Name, c.NotEnforced)
+		}
+	}
+
+	written := ddl.CreateTable(table)
+	again, err := convert.Convert(strings.NewReader(written+";"), option)
+	if err != nil {
+		t.Fatalf("%s\n%v", written, err)
+	}
+	if got := ddl.CreateTable(again.Table("account")); got != written {
+		t.Errorf("converted again:\n%s\nwant:\n%s", got, written)
+	}
+}
+
+// TestMySQL80Alter converts the MySQL 8.0 column and check attributes set by versioned comments
+// and by ALTER TABLE after the table is created.
+func TestMySQL80Alter(t *testing.T) {
+	const create = "CREATE TABLE `t` (`a` int /*!80023 INVISIBLE */, `b` int, " +
+		"CONSTRAINT `c1` CHECK (`a` > 0) /*!80016 NOT ENFORCED */);\n"
+	tests := []struct {
+		name        string
+		alter       string
+		invisible   map[string]bool
+		notEnforced bool
+		err         string
+	}{
+		{
+			name:        "versioned comments",
+			invisible:   map[string]bool{"a": true, "b": false},
+			notEnforced: true,
+		},
+		{
+			name:        "set invisible",
+			alter:       "ALTER TABLE `t` ALTER COLUMN `b` SET INVISIBLE;",
+			invisible:   map[string]bool{"a": true, "b": true},
+			notEnforced: true,
+		},
+		{
+			name:        "set visible among others",
+			alter:       "ALTER TABLE `t` ADD `c` int, ALTER a SET VISIBLE, ALTER COLUMN `b` SET INVISIBLE;",
+			invisible:   map[string]bool{"a": false, "b": true, "c": false},
+			notEnforced: true,
+		},
+		{
+			name:      "alter check enforced",
+			alter:     "ALTER TABLE `t` ALTER CHECK `c1` ENFORCED;",
+			invisible: map[string]bool{"a": true, "b": false},
+		},
+		{
+			name:        "alter constraint not enforced",
+			alter:       "ALTER TABLE `t` ALTER CONSTRAINT c1 ENFORCED, ALTER CONSTRAINT `c1` NOT ENFORCED, ADD `d` int;",
+			invisible:   map[string]bool{"a": true, "b": false, "d": false},
+			notEnforced: true,
+		},
+		{
+			name:      "last alter applies",
+			alter:     "ALTER TABLE `t` ALTER CONSTRAINT `c1` NOT ENFORCED, ALTER CHECK c1 ENFORCED, ALTER b SET INVISIBLE, ALTER b SET VISIBLE;",
+			invisible: map[string]bool{"a": true, "b": false},
+		},
+		{
+			name:  "unknown column",
+			alter: "ALTER TABLE `t` ALTER COLUMN `x` SET INVISIBLE;",
+			err:   "unknown column x in table t",
+		},
+		{
+			name:  "unknown check",
+			alter: "ALTER TABLE `t` ALTER CHECK `c2` NOT ENFORCED;",
+			err:   "unknown check constraint c2 in table t",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			option := convert.DefaultOption()
+			option.Warnf = nil
+			s, err := convert.Convert(strings.NewReader(create+tt.alter), option)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			table := s.Table("t")
+			if len(table.Columns) != len(tt.invisible) {
+				t.Errorf("%d columns, want %d", len(table.Columns), len(tt.invisible))
+			}
+			for name, want := range tt.invisible {
+				if c := table.Column(name); c == nil || c.Invisible != want {
+					t.Errorf("column %s: %+v, want invisible %v", name, c, want)
+				}
+			}
+			if c := table.Constraint("c1"); c == nil || c.NotEnforced != tt.notEnforced {
+				t.Errorf("check c1: %+v, want not enforced %v", c, tt.notEnforced)
+			}
+		})
+	}
+}
+
+// TestDialects converts the ddl of the other dialects, the attributes the models
+// do not show are checked in the schema and in the tables written back by ddl.
+func TestDialects(t *testing.T) {
+	tests := []struct {
+		dialect string
+		path    string
+		// tables are the definitions written back by ddl which must be in the tables
+		tables map[string][]string
+		// foreign dialects are not MySQL compatible, the tables written back are not converted again
+		foreign bool
+	}{
+		{
+			dialect: convert.DialectTiDB,
+			path:    "testdata/tidb/tidb.sql",
+			tables: map[string][]string{
+				"order": {
+					"`id` bigint(20) NOT NULL /*T![auto_rand] AUTO_RANDOM(5) */",
+					"PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */",
+					"/*T![auto_rand_base] AUTO_RANDOM_BASE=30001 */",
+				},
+				"event_log": {
+					"PRIMARY KEY (`id`) /*T![clustered_index] NONCLUSTERED */",
+					"/*T! SHARD_ROW_ID_BITS=4 */ /*T! PRE_SPLIT_REGIONS=2 */;",
+				},
+				"session": {
+					"`id` bigint NOT NULL /*T![auto_rand] AUTO_RANDOM(5) */",
+					"PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */",
+					"/*T! SHARD_ROW_ID_BITS=2 */ /*T![auto_id_cache] AUTO_ID_CACHE=1 */;",
+				},
+				"visit": {
+					"`id` bigint NOT NULL /*T![auto_rand] AUTO_RANDOM(4) */",
+					"PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */",
+				},
+			},
+		},
+		{
+			dialect: convert.DialectMariaDB,
+			path:    "testdata/mariadb/mariadb.sql",
+			tables: map[string][]string{
+				"invoice": {
+					"`id` bigint NOT NULL DEFAULT NEXT VALUE FOR `invoice_seq`",
+					"`number` bigint NOT NULL DEFAULT nextval(`shop`.`number_seq`)",
+					"`copy` bigint NOT NULL DEFAULT (NEXT VALUE FOR invoice_seq)",
+					"`secret` varchar(64) NULL DEFAULT NULL INVISIBLE",
+				},
+				"price": {
+					"`note` text NULL WITHOUT SYSTEM VERSIONING",
+					"`row_start` timestamp(6) GENERATED ALWAYS AS ROW START INVISIBLE",
+					"`row_end` timestamp(6) GENERATED ALWAYS AS ROW END INVISIBLE",
+					"PERIOD FOR SYSTEM_TIME(`row_start`, `row_end`)",
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 WITH SYSTEM VERSIONING;",
+				},
+				"audit": {
+					"`state` varchar(16) NOT NULL WITH SYSTEM VERSIONING",
+				},
+			},
+		},
+		{
+			dialect: convert.DialectPostgres,
+			path:    "testdata/postgres/postgres.sql",
+			foreign: true,
+			tables: map[string][]string{
+				"users": {
+					"`id` int NOT NULL AUTO_INCREMENT",
+					"`email` varchar(320) NOT NULL COMMENT 'login, unique'",
+					"`displayName` varchar(64) NOT NULL DEFAULT 'anonymous'",
+					"`tags` text[] NULL DEFAULT '{}'",
+					"`created_at` timestamptz NOT NULL DEFAULT now()",
+					"`updated_at` datetime(3) NULL",
+					"PRIMARY KEY (`id`)",
+					"UNIQUE KEY `users_email_key` ((lower((email)::text))) USING BTREE",
+					") COMMENT='registered users';",
+				},
+				"orders": {
+					"`id` bigint NOT NULL AUTO_INCREMENT",
+					"`status` enum('pending','paid','shipped') NOT NULL DEFAULT 'pending'",
+					"`note` text NULL",
+					"`handling_time` interval NULL",
+					"`weight` double NULL",
+					"KEY `orders_user_id_idx` (`user_id`,`created` DESC)",
+					"FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE",
+				},
+				"line_items": {
+					"`id` int NOT NULL AUTO_INCREMENT",
+					"`amount` numeric GENERATED ALWAYS AS (quantity * 2) STORED",
+					"PRIMARY KEY (`order_id`,`sku`)",
+				},
+			},
+		},
+		{
+			dialect: convert.DialectSQLite,
+			path:    "testdata/sqlite/sqlite.sql",
+			foreign: true,
+			tables: map[string][]string{
+				"users": {
+					"`id` integer NOT NULL AUTO_INCREMENT",
+					"`ratio` integer NULL",
+					"`updated_at` datetime NULL",
+					"UNIQUE KEY `email` (`email`)",
+				},
+				"posts": {
+					"`id` integer NOT NULL AUTO_INCREMENT",
+					"`user_id` bigint NOT NULL",
+					"`view_count` blob NULL",
+					"`slug` text NULL",
+				},
+				"labels": {
+					"`id` int NOT NULL,",
+				},
+				"post_tags": {
+					"`post_id` integer NOT NULL,",
+					") /* WITHOUT ROWID */;",
+				},
+				"settings": {
+					"`size` int GENERATED ALWAYS AS (length(`value`)) STORED",
+					") /* STRICT, WITHOUT ROWID */;",
+				},
+				"events": {
+					"`id` integer NOT NULL,",
+					"PRIMARY KEY (`id` DESC)",
+				},
+			},
+		},
+		{
+			dialect: convert.DialectTSQL,
+			path:    "testdata/tsql/tsql.sql",
+			foreign: true,
+			tables: map[string][]string{
+				"Users": {
+					"`Id` int NOT NULL AUTO_INCREMENT",
+					"`PublicId` uuid NOT NULL DEFAULT newsequentialid()",
+					"`Bio` longtext NULL",
+					"`Avatar` longblob NULL",
+					"`Phone` varchar(20) NULL",
+					"`Balance` decimal(19,4) NOT NULL DEFAULT 0",
+					"`Score` double NULL",
+					"`IsActive` boolean NOT NULL DEFAULT 1",
+					"`CreatedAt` datetime(7) NOT NULL DEFAULT sysutcdatetime()",
+					"`RowVersion` binary(8) NOT NULL",
+					"PRIMARY KEY (`Id`) /* CLUSTERED */",
+				},
+				"Orders": {
+					"`OrderID` bigint NOT NULL AUTO_INCREMENT",
+					"`Note` varchar(1000) NOT NULL",
+					"`Shipped` boolean NOT NULL DEFAULT 0",
+					"KEY `IX_Orders_PlacedAt` (`PlacedAt` DESC) /* NONCLUSTERED */",
+					"KEY `IX_Orders_User` (`UserID`)",
+					"CONSTRAINT `FK_Orders_Users` FOREIGN KEY (`UserID`) REFERENCES `Users` (`Id`) ON DELETE CASCADE",
+					"CONSTRAINT `CK_Orders_Coupon` CHECK ((len([Coupon])>(3)))",
+				},
+				"Order Items": {
+					"PRIMARY KEY (`OrderID`,`Sku`)",
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		content, err := os.ReadFile(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		option := convert.DefaultOption()
+		option.Warnf = nil
+		if _, err := convert.Convert(bytes.NewReader(content), option); err == nil {
+			t.Errorf("%s: converted as mysql", test.dialect)
+		}
+		option.Dialect = test.dialect
+		s, err := convert.Convert(bytes.NewReader(content), option)
+		if err != nil {
+			t.Fatalf("%s: %v", test.dialect, err)
+		}
+		for name, definitions := range test.tables {
+			table := s.Table(name)
+			if table == nil {
+				t.Errorf("%s: missing table %s", test.dialect, name)
+				continue
+			}
+			written := ddl.CreateTable(table)
+			for _, definition := range definitions {
+				if !strings.Contains(written, definition) {
+					t.Errorf("%s: table %s has no %s:\n%s", test.dialect, name, definition, written)
+				}
+			}
+			if test.foreign {
+				continue
+			}
+			again, err := convert.Convert(strings.NewReader(written), option)
+			if err != nil {
+				t.Fatalf("%s:\n%s\n%v", test.dialect, written, err)
+			}
+			if got := ddl.CreateTable(again.Table(name)); got != written {
+				t.Errorf("%s: converted again:\n%s\nwant:\n%s", test.dialect, got, written)
+			}
+		}
+	}
+
+	option := convert.DefaultOption()
+	option.Dialect = "oracle"
+	if _, err := convert.Convert(strings.NewReader("CREATE TABLE t (id int);"), option); err == nil {
+		t.Errorf("unknown dialect: no error")
+	}
+}
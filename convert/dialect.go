@@ -0,0 +1,411 @@
+package convert
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	gen "github.com/er1c-zh/sql-to-gorm/antlr4_gen"
+)
+
+// Dialects whose DDL is parsed, the values of Option.Dialect.
+const (
+	DialectMySQL    = "mysql"
+	DialectMariaDB  = "mariadb"
+	DialectTiDB     = "tidb"
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite"
+	DialectTSQL     = "tsql"
+)
+
+// Dialects are the supported dialects, the first one is the default.
+var Dialects = []string{DialectMySQL, DialectMariaDB, DialectTiDB, DialectPostgres, DialectSQLite, DialectTSQL}
+
+// frontends read the dialects which are not MySQL compatible, see frontend.
+var frontends = map[string]frontend{
+	DialectPostgres: postgres{},
+	DialectSQLite:   sqlite{},
+	DialectTSQL:     tsql{},
+}
+
+// MySQLCompatible reports whether dialect is read by the MySQL grammar,
+// e.g. its SHOW CREATE TABLE can be parsed.
+func MySQLCompatible(dialect string) bool {
+	_, ok := frontends[dialect]
+	return !ok
+}
+
+func validDialect(dialect string) bool {
+	for _, d := range Dialects {
+		if d == dialect {
+			return true
+		}
+	}
+	return false
+}
+
+// The DDL of a dialect is rewritten for the grammar like MySQL 8.0 DDL is, see rewrite8.
+const (
+	// AUTO_RANDOM[(shard bits[, range])] columns of tidb are blanked, value is the shard bits.
+	rewriteAutoRandom = "AUTO_RANDOM"
+	// CLUSTERED and NONCLUSTERED primary keys of tidb are blanked.
+	rewriteClustered    = "CLUSTERED"
+	rewriteNonclustered = "NONCLUSTERED"
+	// table options of tidb, e.g. SHARD_ROW_ID_BITS=4, are blanked, value is NAME=value.
+	rewriteTableOption = "TABLE OPTION"
+	// a sequence value as the default of a mariadb column, e.g. NEXT VALUE FOR s,
+	// becomes a string, value is the expression.
+	rewriteDefault = "DEFAULT"
+	// WITH and WITHOUT SYSTEM VERSIONING of mariadb tables and columns are blanked.
+	rewriteWithVersioning    = "WITH SYSTEM VERSIONING"
+	rewriteWithoutVersioning = "WITHOUT SYSTEM VERSIONING"
+	// the period columns of a mariadb system-versioned table, [GENERATED ALWAYS] AS ROW START
+	// and AS ROW END, are blanked, and so is PERIOD FOR SYSTEM_TIME (start, end).
+	rewriteRowStart = "ROW START"
+	rewriteRowEnd   = "ROW END"
+	rewritePeriod   = "PERIOD FOR SYSTEM_TIME"
+)
+
+// tidbTableOptions are the table options of tidb with a number as value.
+var tidbTableOptions = map[string]bool{
+	"SHARD_ROW_ID_BITS": true,
+	"PRE_SPLIT_REGIONS": true,
+	"AUTO_RANDOM_BASE":  true,
+	"AUTO_ID_CACHE":     true,
+}
+
+// tidbFeatures are the features of the /*T![feature] ... */ comments read for tidb,
+// those of others, e.g. placement, are left as comments like an older tidb does.
+var tidbFeatures = map[string]bool{
+	"auto_rand":       true,
+	"auto_rand_base":  true,
+	"clustered_index": true,
+	"auto_id_cache":   true,
+}
+
+// rewriteDialect rewrites the construct of dialect or of MySQL 8.0 at offset of statement,
+// in place, false if there is none the parser may have failed on.
+func rewriteDialect(dialect string, statement []rune, offset int) (rewrite, bool) {
+	if r, ok := rewrite8(statement, offset); ok {
+		return r, true
+	}
+	if offset < 0 || offset >= len(statement) {
+		return rewrite{}, false
+	}
+	switch dialect {
+	case DialectTiDB:
+		return rewriteTiDB(statement, offset)
+	case DialectMariaDB:
+		return rewriteMariaDB(statement, offset)
+	}
+	return rewrite{}, false
+}
+
+func rewriteTiDB(statement []rune, offset int) (rewrite, bool) {
+	word, end := wordAt(statement, offset)
+	switch {
+	case word == rewriteAutoRandom:
+		value := ""
+		if open := skipSpaces(statement, end); open < len(statement) && statement[open] == '(' {
+			close := closingParen(statement, open)
+			if close < 0 {
+				return rewrite{}, false
+			}
+			value = strings.TrimSpace(strings.Split(string(statement[open+1:close]), ",")[0])
+			end = close + 1
+		}
+		blankRunes(statement[offset:end])
+		return rewrite{kind: rewriteAutoRandom, offset: offset, value: value}, true
+	case word == rewriteClustered || word == rewriteNonclustered:
+		blankRunes(statement[offset:end])
+		return rewrite{kind: word, offset: offset}, true
+	case tidbTableOptions[word]:
+		start := skipSpaces(statement, end)
+		if start < len(statement) && statement[start] == '=' {
+			start = skipSpaces(statement, start+1)
+		}
+		valueEnd := start
+		for valueEnd < len(statement) && '0' <= statement[valueEnd] && statement[valueEnd] <= '9' {
+			valueEnd++
+		}
+		if valueEnd == start {
+			return rewrite{}, false
+		}
+		value := word + "=" + string(statement[start:valueEnd])
+		blankRunes(statement[offset:valueEnd])
+		return rewrite{kind: rewriteTableOption, offset: offset, value: value}, true
+	}
+	return rewrite{}, false
+}
+
+func rewriteMariaDB(statement []rune, offset int) (rewrite, bool) {
+	// PERIOD and NEXT may be taken for names, the parser fails after them
+	if start := phraseStart(statement, offset, "PERIOD", "NEXT", "PREVIOUS"); start >= 0 {
+		offset = start
+	}
+	word, end := wordAt(statement, offset)
+	switch word {
+	case "NEXT", "PREVIOUS", "NEXTVAL", "LASTVAL":
+		// DEFAULT NEXT VALUE FOR s or DEFAULT (NEXT VALUE FOR s)
+		before := skipSpacesBack(statement, offset)
+		if before > 0 && statement[before-1] == '(' {
+			before--
+		}
+		if word, _ := wordBefore(statement, before); word != "DEFAULT" {
+			return rewrite{}, false
+		}
+		if word == "NEXT" || word == "PREVIOUS" {
+			value, valueEnd := wordAt(statement, skipSpaces(statement, end))
+			of, ofEnd := wordAt(statement, skipSpaces(statement, valueEnd))
+			if value != "VALUE" || of != "FOR" {
+				return rewrite{}, false
+			}
+			end = nameEnd(statement, skipSpaces(statement, ofEnd))
+		} else {
+			open := skipSpaces(statement, end)
+			if open == len(statement) || statement[open] != '(' {
+				return rewrite{}, false
+			}
+			end = closingParen(statement, open) + 1
+		}
+		if end <= offset+1 {
+			return rewrite{}, false
+		}
+		value := string(statement[offset:end])
+		statement[offset], statement[end-1] = '\'', '\''
+		for i := offset + 1; i < end-1; i++ {
+			if statement[i] != '\n' {
+				statement[i] = '_'
+			}
+		}
+		return rewrite{kind: rewriteDefault, offset: offset, value: value}, true
+	case "WITH", "WITHOUT":
+		system, systemEnd := wordAt(statement, skipSpaces(statement, end))
+		versioning, versioningEnd := wordAt(statement, skipSpaces(statement, systemEnd))
+		if system != "SYSTEM" || versioning != "VERSIONING" {
+			return rewrite{}, false
+		}
+		blankRunes(statement[offset:versioningEnd])
+		kind := rewriteWithVersioning
+		if word == "WITHOUT" {
+			kind = rewriteWithoutVersioning
+		}
+		return rewrite{kind: kind, offset: offset}, true
+	case "ROW":
+		which, whichEnd := wordAt(statement, skipSpaces(statement, end))
+		if which != "START" && which != "END" {
+			return rewrite{}, false
+		}
+		as, start := wordBefore(statement, offset)
+		if as != "AS" {
+			return rewrite{}, false
+		}
+		if always, i := wordBefore(statement, start); always == "ALWAYS" {
+			if generated, j := wordBefore(statement, i); generated == "GENERATED" {
+				start = j
+			}
+		}
+		blankRunes(statement[start:whichEnd])
+		kind := rewriteRowStart
+		if which == "END" {
+			kind = rewriteRowEnd
+		}
+		return rewrite{kind: kind, offset: start}, true
+	case "PERIOD":
+		of, ofEnd := wordAt(statement, skipSpaces(statement, end))
+		name, nameEnd := wordAt(statement, skipSpaces(statement, ofEnd))
+		open := skipSpaces(statement, nameEnd)
+		if of != "FOR" || name != "SYSTEM_TIME" || open == len(statement) || statement[open] != '(' {
+			return rewrite{}, false
+		}
+		close := closingParen(statement, open)
+		if close < 0 {
+			return rewrite{}, false
+		}
+		// the comma before the definition, or after it if it is the first one
+		start, stop := offset, close+1
+		if before := skipSpacesBack(statement, offset); before > 0 && statement[before-1] == ',' {
+			start = before - 1
+		} else if after := skipSpaces(statement, stop); after < len(statement) && statement[after] == ',' {
+			stop = after + 1
+		}
+		blankRunes(statement[start:stop])
+		return rewrite{kind: rewritePeriod, offset: start}, true
+	}
+	return rewrite{}, false
+}
+
+// phraseStart returns the offset of the first of words found at offset or up to two words
+// before it, e.g. of PERIOD when offset is in PERIOD FOR SYSTEM_TIME, -1 if none is.
+func phraseStart(s []rune, offset int, words ...string) int {
+	for i, n := offset, 0; i >= 0 && n < 3; n++ {
+		word, _ := wordAt(s, i)
+		for _, w := range words {
+			if word == w {
+				return i
+			}
+		}
+		_, i = wordBefore(s, i)
+	}
+	return -1
+}
+
+// nameEnd returns the end of the possibly quoted and qualified name at i, e.g. `db`.seq.
+func nameEnd(s []rune, i int) int {
+	for i < len(s) {
+		if s[i] == '`' {
+			i = closingQuote(s, i) + 1
+		} else {
+			for i < len(s) && (unicode.IsLetter(s[i]) || unicode.IsDigit(s[i]) || s[i] == '_' || s[i] == '$') {
+				i++
+			}
+		}
+		if i >= len(s) || s[i] != '.' {
+			break
+		}
+		i++
+	}
+	if i > len(s) {
+		return len(s)
+	}
+	return i
+}
+
+// skipSpacesBack returns the offset after the last rune before i which is not a space.
+func skipSpacesBack(s []rune, i int) int {
+	for i > 0 && unicode.IsSpace(s[i-1]) {
+		i--
+	}
+	return i
+}
+
+// unwrapComments blanks the delimiters of the executable comments of dialect in statement
+// so the parser reads what they contain, e.g. /*!80023 INVISIBLE */ of mysql, which all
+// MySQL compatible servers execute, /*T![clustered_index] CLUSTERED */ for tidb
+// and /*M!100301 ... */ for mariadb. Other comments are left to the lexer.
+func unwrapComments(dialect string, statement []rune) {
+	for i := 0; i < len(statement); i++ {
+		switch c := statement[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = closingQuote(statement, i)
+		case c == '#' || c == '-' && i+1 < len(statement) && statement[i+1] == '-' &&
+			(i+2 == len(statement) || unicode.IsSpace(statement[i+2])):
+			for i < len(statement) && statement[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(statement) && statement[i+1] == '*':
+			end := i + 2
+			for end+1 < len(statement) && (statement[end] != '*' || statement[end+1] != '/') {
+				end++
+			}
+			if end+1 >= len(statement) {
+				return
+			}
+			if open := executableComment(dialect, statement[i:end]); open > 0 {
+				blankRunes(statement[i : i+open])
+				blankRunes(statement[end : end+2])
+			}
+			i = end + 1
+		}
+	}
+}
+
+// executableComment returns the length of the start of comment, which does not
+// include its end, if it is executed by dialect, 0 if it is not.
+func executableComment(dialect string, comment []rune) int {
+	text := string(comment)
+	switch {
+	case strings.HasPrefix(text, "/*!"):
+		// the comment is executed by servers of at least the version, e.g. 80023 for 8.0.23,
+		// the DDL is read as the newest one would
+		n := len("/*!")
+		for n < len(comment) && unicode.IsDigit(comment[n]) {
+			n++
+		}
+		return n
+	case dialect == DialectTiDB && strings.HasPrefix(text, "/*T!"):
+		if !strings.HasPrefix(text, "/*T![") {
+			return len("/*T!")
+		}
+		end := strings.IndexByte(text, ']')
+		if end < 0 {
+			return 0
+		}
+		for _, feature := range strings.Split(text[len("/*T!["):end], ",") {
+			if !tidbFeatures[strings.TrimSpace(feature)] {
+				return 0
+			}
+		}
+		return len([]rune(text[:end+1]))
+	case dialect == DialectMariaDB && strings.HasPrefix(text, "/*M!"):
+		n := len("/*M!")
+		for n < len(comment) && unicode.IsDigit(comment[n]) {
+			n++
+		}
+		return n
+	}
+	return 0
+}
+
+// setDialectColumnAttributes sets the attributes of the current column rewritten in its definition.
+func (l *Listener) setDialectColumnAttributes(ctx *gen.ColumnDefinitionContext) {
+	for _, r := range l.rewritten(ctx, rewriteAutoRandom) {
+		bits := "5" // the default of tidb
+		if atoi(r.value) != nil {
+			bits = r.value
+		}
+		l.CurrentCol.SetOption(rewriteAutoRandom, bits)
+	}
+	for _, kind := range []string{rewriteRowStart, rewriteRowEnd} {
+		if len(l.rewritten(ctx, kind)) > 0 {
+			l.CurrentCol.SystemTime = kind
+			l.CurrentCol.Nullable = false
+		}
+	}
+	for _, kind := range []string{rewriteWithVersioning, rewriteWithoutVersioning} {
+		if len(l.rewritten(ctx, kind)) > 0 {
+			l.CurrentCol.Versioning = strings.TrimSuffix(kind, " SYSTEM VERSIONING")
+		}
+	}
+}
+
+// setDialectTableOptions sets the options of the current table rewritten in its definition.
+func (l *Listener) setDialectTableOptions(ctx *gen.ColumnCreateTableContext) {
+	for _, r := range l.rewritten(ctx, rewriteTableOption) {
+		name, value, _ := strings.Cut(r.value, "=")
+		l.CurrentTable.SetOption(name, value)
+	}
+	// WITH SYSTEM VERSIONING of a column is in its definition
+	end := ctx.CreateDefinitions().GetStop().GetStop()
+	for _, r := range l.rewritten(ctx, rewriteWithVersioning) {
+		if r.offset > end {
+			l.CurrentTable.SetOption(rewriteWithVersioning, "")
+		}
+	}
+}
+
+// clustered returns CLUSTERED or NONCLUSTERED if it is rewritten in or after
+// the primary key ctx, empty if neither is.
+func (l *Listener) clustered(ctx antlr.ParserRuleContext) string {
+	for _, kind := range []string{rewriteClustered, rewriteNonclustered} {
+		if len(l.rewritten(ctx, kind)) > 0 {
+			return kind
+		}
+	}
+	return ""
+}
+
+// sequenceDefault returns value, the default dv as written, with the sequence values
+// rewritten in it written back, e.g. NEXT VALUE FOR s.
+func (l *Listener) sequenceDefault(dv antlr.ParserRuleContext, value string) string {
+	start := dv.GetStart().GetStart()
+	runes := []rune(value)
+	for _, r := range l.rewritten(dv, rewriteDefault) {
+		i, n := r.offset-start, len([]rune(r.value))
+		if i < 0 || i+n > len(runes) {
+			continue
+		}
+		copy(runes[i:i+n], []rune(r.value))
+	}
+	return string(runes)
+}
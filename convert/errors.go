@@ -0,0 +1,83 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// SyntaxError is an error located in an input.
+type SyntaxError struct {
+	Source string
+	Line   int
+	Column int
+	Msg    string
+	// Snippet is the offending line of the input, may be empty.
+	Snippet string
+}
+
+func (e *SyntaxError) Error() string {
+	buf := new(strings.Builder)
+	buf.WriteString(fmt.Sprintf("%s:%d:%d: %s", e.Source, e.Line, e.Column+1, e.Msg))
+	if e.Snippet != "" {
+		buf.WriteString("\n    " + strings.ReplaceAll(e.Snippet, "\t", " "))
+		buf.WriteString("\n    " + strings.Repeat(" ", e.Column) + "^")
+	}
+	return buf.String()
+}
+
+// ErrorList is a list of errors reported as one.
+type ErrorList []error
+
+func (l ErrorList) Error() string {
+	msgList := make([]string, 0, len(l))
+	for _, err := range l {
+		msgList = append(msgList, err.Error())
+	}
+	return strings.Join(msgList, "\n")
+}
+
+// Err returns nil if the list is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// ErrorListener collects lexer and parser errors of one input
+// instead of printing them to the console.
+type ErrorListener struct {
+	*antlr.DefaultErrorListener
+	Source string
+	Lines  []string
+	Errors ErrorList
+}
+
+func NewErrorListener(source string, content string) *ErrorListener {
+	return &ErrorListener{
+		DefaultErrorListener: antlr.NewDefaultErrorListener(),
+		Source:               source,
+		Lines:                strings.Split(content, "\n"),
+	}
+}
+
+func (l *ErrorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{},
+	line, column int, msg string, e antlr.RecognitionException) {
+	l.Errors = append(l.Errors, l.NewError(line, column, msg))
+}
+
+// NewError builds an error at line (1-based) and column (0-based) of the input.
+func (l *ErrorListener) NewError(line, column int, msg string) *SyntaxError {
+	err := &SyntaxError{
+		Source: l.Source,
+		Line:   line,
+		Column: column,
+		Msg:    msg,
+	}
+	if line > 0 && line <= len(l.Lines) {
+		err.Snippet = strings.TrimRight(l.Lines[line-1], "\r")
+	}
+	return err
+}
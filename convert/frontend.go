@@ -0,0 +1,1442 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// The DDL of a dialect which is not MySQL can not be rewritten for the grammar, it is read
+// by a hand-written parser instead, ddlParser. It reads the statements which change tables
+// the way the dialects share, and a frontend reads what is particular to one: its types,
+// column constraints, table options and statements like COMMENT ON.
+// The tables are filled like the listener fills them, the types are named like the MySQL
+// types they are equivalent to, e.g. timestamp of PostgreSQL is datetime, so the models
+// are rendered with the same type map.
+
+// frontend reads the DDL particular to a dialect, see ddlParser.
+type frontend interface {
+	syntax() dialectSyntax
+	// setType sets the type of col written as t and what the type implies,
+	// e.g. serial columns of PostgreSQL auto increment.
+	setType(p *ddlParser, col *schema.Column, t sqlType)
+	// setDefault sets the default of col written as value.
+	setDefault(col *schema.Column, value string)
+	// columnConstraint reads a column constraint of the dialect, false if there is none at p.
+	columnConstraint(p *ddlParser, col *schema.Column) bool
+	// tableOptions reads what follows the definitions of CREATE TABLE t.
+	tableOptions(p *ddlParser, t *schema.Table)
+	// statement reads a statement of kind particular to the dialect, see ddlParser.kind,
+	// the changes are applied by apply. ok is false if the shared parser reads it.
+	statement(p *ddlParser, kind string) (apply func(), ok bool)
+	// skip moves to the end of a statement of kind which does not end like others,
+	// e.g. a trigger whose body has statements, false if kind is not one.
+	skip(p *ddlParser, kind string) bool
+}
+
+// dialectSyntax is how a dialect writes names, strings and statements.
+type dialectSyntax struct {
+	// quotes maps the characters opening quoted names to those closing them,
+	// a closing character in a name is doubled.
+	quotes map[byte]byte
+	// dollarQuotes are the $tag$ ... $tag$ strings of PostgreSQL.
+	dollarQuotes bool
+	// metaCommands are the lines starting with \ for psql, e.g. \connect, they are skipped.
+	metaCommands bool
+	// batches end with GO on a line of its own like sqlcmd reads them,
+	// and statements need not end with ;.
+	batches bool
+	// wordStart are the characters besides letters and _ which start words, e.g. # of T-SQL.
+	wordStart string
+	// foldNames folds unquoted names to lower case like PostgreSQL does.
+	foldNames bool
+	// columnWords are the words starting the column constraints of the dialect,
+	// they end the type and the default of a column, see columnWords.
+	columnWords []string
+	// statementWords start a statement which need not follow a ; in a batch, e.g. CREATE,
+	// they end the clauses which are skipped and the type and the default of a column.
+	statementWords []string
+}
+
+// columnWords are the words starting the column constraints of all dialects,
+// and USING which may follow the new type of a column.
+var columnWords = []string{
+	"CONSTRAINT", "NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE",
+	"REFERENCES", "CHECK", "COLLATE", "GENERATED", "AS", "USING",
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	// tokenWord is a keyword or a name which is not quoted.
+	tokenWord
+	// tokenName is a quoted name.
+	tokenName
+	tokenString
+	tokenNumber
+	// tokenSymbol is an operator or a punctuation, e.g. ( or ::.
+	tokenSymbol
+	// tokenEnd is ; or GO, which end a statement.
+	tokenEnd
+)
+
+type token struct {
+	kind tokenKind
+	// text is a word as written, or the value of a quoted name or a string.
+	text string
+	// start and end are the byte offsets of the token in the script.
+	start, end int
+}
+
+// ddlError is a syntax error at a byte offset of the script. It is raised with panic
+// while a statement is read and recovered by ddlParser.statement.
+type ddlError struct {
+	offset int
+	msg    string
+}
+
+// lex splits script into tokens, leaving out spaces and comments. After an unterminated
+// string, name or comment, the tokens of the statements ended before it are returned with an error.
+func lex(syntax dialectSyntax, script string) ([]token, *ddlError) {
+	tokens := make([]token, 0, len(script)/4)
+	// lineStart is set until the line has anything but spaces
+	lineStart := true
+	unterminated := func(offset int, what string) ([]token, *ddlError) {
+		end := len(tokens)
+		for end > 0 && tokens[end-1].kind != tokenEnd {
+			end--
+		}
+		return tokens[:end], &ddlError{offset: offset, msg: "unterminated " + what}
+	}
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == '\n':
+			lineStart = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		case strings.HasPrefix(script[i:], "--"),
+			syntax.metaCommands && lineStart && c == '\\':
+			i = lineEnd(script, i)
+			continue
+		case strings.HasPrefix(script[i:], "/*"):
+			end := commentEnd(script, i)
+			if end < 0 {
+				return unterminated(i, "comment")
+			}
+			i = end
+			continue
+		}
+		if syntax.batches && lineStart {
+			if end, ok := batchEnd(script, i); ok {
+				tokens = append(tokens, token{kind: tokenEnd, text: "GO", start: i, end: end})
+				i = end
+				continue
+			}
+		}
+		lineStart = false
+
+		t := token{start: i}
+		switch {
+		case c == '\'':
+			t.kind = tokenString
+			t.end, t.text = quoted(script, i, '\'', false)
+		case strings.IndexByte("EeNnXxBb", c) >= 0 && i+1 < len(script) && script[i+1] == '\'':
+			// E'\n', N'unicode', X'0f' and B'0101'
+			t.kind = tokenString
+			t.end, t.text = quoted(script, i+1, '\'', c == 'E' || c == 'e')
+		case syntax.quotes[c] != 0:
+			t.kind = tokenName
+			t.end, t.text = quoted(script, i, syntax.quotes[c], false)
+		case c == '$' && syntax.dollarQuotes && dollarTag(script, i) != "":
+			tag := dollarTag(script, i)
+			end := strings.Index(script[i+len(tag):], tag)
+			if end < 0 {
+				return unterminated(i, "string")
+			}
+			t.kind = tokenString
+			t.text = script[i+len(tag) : i+len(tag)+end]
+			t.end = i + len(tag) + end + len(tag)
+		case isDigit(c) || c == '.' && i+1 < len(script) && isDigit(script[i+1]):
+			t.kind = tokenNumber
+			t.end = numberEnd(script, i)
+			t.text = script[i:t.end]
+		case c == ';':
+			t.kind, t.text, t.end = tokenEnd, ";", i+1
+		case strings.HasPrefix(script[i:], "::"):
+			t.kind, t.text, t.end = tokenSymbol, "::", i+2
+		default:
+			r, size := utf8.DecodeRuneInString(script[i:])
+			if unicode.IsLetter(r) || r == '_' || strings.ContainsRune(syntax.wordStart, r) {
+				t.kind = tokenWord
+				t.end = i + size
+				for t.end < len(script) {
+					r, size := utf8.DecodeRuneInString(script[t.end:])
+					if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$' &&
+						!strings.ContainsRune(syntax.wordStart, r) {
+						break
+					}
+					t.end += size
+				}
+			} else {
+				t.kind, t.end = tokenSymbol, i+size
+			}
+			t.text = script[i:t.end]
+		}
+		if t.end < 0 {
+			return unterminated(i, "string or name")
+		}
+		tokens = append(tokens, t)
+		i = t.end
+	}
+	return tokens, nil
+}
+
+// quoted reads the string or the name quoted at open up to close, a doubled close is
+// one close, and with escapes a backslash escapes the next character.
+// It returns the offset after close and the value, -1 if it is not closed.
+func quoted(s string, open int, close byte, escapes bool) (int, string) {
+	buf := new(strings.Builder)
+	for i := open + 1; i < len(s); i++ {
+		switch {
+		case s[i] == close && i+1 < len(s) && s[i+1] == close:
+			buf.WriteByte(close)
+			i++
+		case s[i] == close:
+			return i + 1, buf.String()
+		case escapes && s[i] == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			default:
+				buf.WriteByte(s[i])
+			}
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	return -1, ""
+}
+
+// dollarTag returns the tag of the dollar quote at i, e.g. $$ or $body$, empty if there is none.
+func dollarTag(s string, i int) string {
+	end := i + 1
+	for end < len(s) && (s[end] == '_' || isDigit(s[end]) && end > i+1 ||
+		'a' <= s[end]|0x20 && s[end]|0x20 <= 'z') {
+		end++
+	}
+	if end < len(s) && s[end] == '$' {
+		return s[i : end+1]
+	}
+	return ""
+}
+
+// commentEnd returns the offset after the /* */ comment at i, -1 if it is not closed.
+// Comments nest like in PostgreSQL and T-SQL.
+func commentEnd(s string, i int) int {
+	depth := 0
+	for i < len(s)-1 {
+		switch s[i : i+2] {
+		case "/*":
+			depth++
+			i += 2
+		case "*/":
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return -1
+}
+
+// batchEnd returns the end of the line of GO at i, which ends a batch of T-SQL,
+// false if the line is not GO, which may be followed by a count.
+func batchEnd(s string, i int) (int, bool) {
+	if len(s) < i+2 || !strings.EqualFold(s[i:i+2], "GO") {
+		return 0, false
+	}
+	end := lineEnd(s, i)
+	rest := strings.TrimSpace(s[i+2 : end])
+	if comment := strings.Index(rest, "--"); comment >= 0 {
+		rest = strings.TrimSpace(rest[:comment])
+	}
+	if strings.TrimLeft(rest, "0123456789") != "" {
+		return 0, false
+	}
+	return end, true
+}
+
+// lineEnd returns the offset of the line break ending the line of i, or the end of s.
+func lineEnd(s string, i int) int {
+	if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(s)
+}
+
+func numberEnd(s string, i int) int {
+	for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+		i++
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			for i = j; i < len(s) && isDigit(s[i]); i++ {
+			}
+		}
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// ddlParser reads the statements of a script in a dialect which is not MySQL, see frontend.
+// The statements are read and applied one by one, a statement with errors is reported
+// and left out like the listener does.
+type ddlParser struct {
+	l      *Listener
+	fe     frontend
+	syntax dialectSyntax
+	script string
+	tokens []token
+	pos    int
+}
+
+// parseDDL reads the statements of content with fe, errors are reported to the error listener.
+func (l *Listener) parseDDL(fe frontend, content string) {
+	p := &ddlParser{l: l, fe: fe, syntax: fe.syntax(), script: content}
+	var err *ddlError
+	p.tokens, err = lex(p.syntax, content)
+	for p.peek().kind != tokenEOF {
+		p.statement()
+	}
+	if err != nil {
+		p.report(err)
+	}
+}
+
+// statement reads the statement at p and applies it, or skips it if it is not of a kind parsed.
+func (p *ddlParser) statement() {
+	start := p.pos
+	kind := p.kind()
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		e, ok := r.(*ddlError)
+		if !ok {
+			e = &ddlError{offset: p.tokens[start].start, msg: fmt.Sprintf("internal error: %v", r)}
+		}
+		p.report(e)
+		p.l.CurrentTable = nil
+		p.l.CurrentCol = nil
+		p.pos = start
+		p.skip(kind)
+		if p.peek().kind == tokenEnd || p.pos == start {
+			p.pos++
+		}
+	}()
+
+	var apply func()
+	if !p.l.statements[kind] {
+		p.skip(kind)
+	} else if fn, ok := p.fe.statement(p, kind); ok {
+		apply = fn
+	} else {
+		switch kind {
+		case "CREATE TABLE":
+			apply = p.createTable()
+		case "CREATE INDEX":
+			apply = p.createIndex()
+		case "ALTER TABLE":
+			apply = p.alterTable()
+		case "DROP TABLE":
+			apply = p.dropTable()
+		case "DROP INDEX":
+			apply = p.dropIndex()
+		case "USE":
+			p.expect("USE")
+			name := p.name()
+			apply = func() { p.l.Database = name }
+		default:
+			p.skip(kind)
+		}
+	}
+	p.end()
+	if apply != nil {
+		apply()
+	}
+}
+
+// kind returns the kind of the statement at p like Statement.Kind does, e.g. CREATE INDEX
+// for CREATE UNIQUE INDEX, empty if the statement is empty.
+func (p *ddlParser) kind() string {
+	t := p.peek()
+	if t.kind != tokenWord {
+		return ""
+	}
+	keyword := strings.ToUpper(t.text)
+	switch keyword {
+	case "CREATE", "ALTER", "DROP", "RENAME":
+	default:
+		return keyword
+	}
+	for i := 1; i < 10; i++ {
+		t := p.at(i)
+		if t.kind == tokenEnd || t.kind == tokenEOF || p.startsStatement(i) {
+			break
+		}
+		if w := strings.ToUpper(t.text); t.kind == tokenWord && objects[w] {
+			return keyword + " " + w
+		}
+	}
+	return keyword
+}
+
+// skip moves to the end of the statement at p, the ; ending it, the end of the batch
+// or the next statement of a batch, parentheses are skipped whole.
+func (p *ddlParser) skip(kind string) {
+	if p.fe.skip(p, kind) {
+		return
+	}
+	start := p.pos
+	depth := 0
+	for {
+		switch t := p.peek(); {
+		case t.kind == tokenEOF, t.kind == tokenEnd:
+			return
+		case depth == 0 && p.pos > start && p.startsStatement(0):
+			return
+		case p.is("("):
+			depth++
+		case p.is(")") && depth > 0:
+			depth--
+		}
+		p.pos++
+	}
+}
+
+// startsStatement reports whether the token n tokens after the next one starts a statement
+// of a batch, see dialectSyntax.statementWords. ALTER of CREATE OR ALTER does not.
+func (p *ddlParser) startsStatement(n int) bool {
+	t := p.at(n)
+	return t.kind == tokenWord && contains(p.syntax.statementWords, t.text) &&
+		!(p.pos+n > 0 && strings.EqualFold(p.tokens[p.pos+n-1].text, "OR"))
+}
+
+// end reads the end of a statement, T-SQL statements need not end with ;.
+func (p *ddlParser) end() {
+	switch t := p.peek(); {
+	case t.kind == tokenEnd:
+		p.pos++
+	case t.kind != tokenEOF && !p.syntax.batches:
+		p.errorf("extraneous input %s expecting ;", p.quote(t))
+	}
+}
+
+// report adds e to the errors of the script.
+func (p *ddlParser) report(e *ddlError) {
+	pos := position{line: 1}
+	pos.advance(p.script, e.offset)
+	el := p.l.errorListener
+	el.Errors = append(el.Errors, el.NewError(pos.line, pos.column, e.msg))
+}
+
+// errorf raises a syntax error at the next token.
+func (p *ddlParser) errorf(format string, args ...interface{}) {
+	panic(&ddlError{offset: p.peek().start, msg: fmt.Sprintf(format, args...)})
+}
+
+// warnf prints a warning about the statement at offset, prefixed by its source and line.
+func (p *ddlParser) warnf(offset int, format string, args ...interface{}) {
+	line := strings.Count(p.script[:offset], "\n") + 1
+	p.l.option.warnf("%s:%d: "+format, append([]interface{}{p.l.CurrentSource, line}, args...)...)
+}
+
+/////////////////////////////////////////////
+// tokens ///////////////////////////////////
+/////////////////////////////////////////////
+
+func (p *ddlParser) peek() token {
+	return p.at(0)
+}
+
+func (p *ddlParser) next() token {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+// at returns the token n tokens after the next one.
+func (p *ddlParser) at(n int) token {
+	if i := p.pos + n; i < len(p.tokens) {
+		return p.tokens[i]
+	}
+	return token{kind: tokenEOF, start: len(p.script), end: len(p.script)}
+}
+
+// is reports whether the next tokens are the keywords or symbols words, case-insensitively.
+func (p *ddlParser) is(words ...string) bool {
+	for i, w := range words {
+		t := p.at(i)
+		if t.kind != tokenWord && t.kind != tokenSymbol && t.kind != tokenEnd || !strings.EqualFold(t.text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// accept reads words if they are next, see is.
+func (p *ddlParser) accept(words ...string) bool {
+	if !p.is(words...) {
+		return false
+	}
+	p.pos += len(words)
+	return true
+}
+
+// expect reads words, raising an error at the first which is not next.
+func (p *ddlParser) expect(words ...string) {
+	for _, w := range words {
+		if !p.accept(w) {
+			p.errorf("mismatched input %s expecting %s", p.quote(p.peek()), strings.ToUpper(w))
+		}
+	}
+}
+
+// quote returns t as written for errors.
+func (p *ddlParser) quote(t token) string {
+	if t.kind == tokenEOF {
+		return "<EOF>"
+	}
+	return "'" + p.script[t.start:t.end] + "'"
+}
+
+// text returns the script from the token at from to the one before to.
+func (p *ddlParser) text(from, to int) string {
+	return p.script[p.tokens[from].start:p.tokens[to-1].end]
+}
+
+// isName reports whether the next token is a name, quoted or not.
+func (p *ddlParser) isName() bool {
+	kind := p.peek().kind
+	return kind == tokenWord || kind == tokenName
+}
+
+// name reads a name, quoted or not.
+func (p *ddlParser) name() string {
+	t := p.peek()
+	if !p.isName() {
+		p.errorf("mismatched input %s expecting a name", p.quote(t))
+	}
+	p.pos++
+	if t.kind == tokenWord && p.syntax.foldNames {
+		return strings.ToLower(t.text)
+	}
+	return t.text
+}
+
+// qualifiedName reads a dotted name like schema.table into its parts.
+func (p *ddlParser) qualifiedName() []string {
+	parts := []string{p.name()}
+	for p.accept(".") {
+		parts = append(parts, p.name())
+	}
+	return parts
+}
+
+// tableName reads a possibly schema-qualified table name,
+// the database is the current database if not qualified.
+func (p *ddlParser) tableName() (database string, name string) {
+	parts := p.qualifiedName()
+	database = strings.Join(parts[:len(parts)-1], ".")
+	if database == "" {
+		database = p.l.Database
+	}
+	return database, parts[len(parts)-1]
+}
+
+// names reads a list of names in parentheses, e.g. the columns of a foreign key.
+func (p *ddlParser) names() []string {
+	p.expect("(")
+	names := []string{p.name()}
+	for p.accept(",") {
+		names = append(names, p.name())
+	}
+	p.expect(")")
+	return names
+}
+
+// expression returns the text of the expression at p, up to a , or a ) which is not
+// in its parentheses, the end of the statement or, after its first token, one of the words stop.
+func (p *ddlParser) expression(stop ...string) string {
+	start := p.pos
+	depth := 0
+loop:
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenEOF, t.kind == tokenEnd:
+			break loop
+		case p.is("("):
+			depth++
+		case p.is(")"):
+			if depth == 0 {
+				break loop
+			}
+			depth--
+		case depth == 0 && p.is(","):
+			break loop
+		case depth == 0 && p.pos > start && t.kind == tokenWord && contains(stop, t.text):
+			break loop
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		p.errorf("mismatched input %s expecting an expression", p.quote(p.peek()))
+	}
+	return p.text(start, p.pos)
+}
+
+// parenthesized reads an expression in parentheses, e.g. of CHECK, and returns it without them.
+func (p *ddlParser) parenthesized() string {
+	p.expect("(")
+	expression := p.expression()
+	p.expect(")")
+	return expression
+}
+
+// parenthesizedList skips a list of expressions in parentheses, e.g. the options of WITH (options).
+func (p *ddlParser) parenthesizedList() {
+	p.expect("(")
+	for !p.is(")") {
+		p.expression()
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect(")")
+}
+
+// rest skips what is left of a definition, the clauses the model does not keep,
+// e.g. WITH (fillfactor=70) of a primary key.
+func (p *ddlParser) rest() {
+	if !p.is(",") && !p.is(")") && p.peek().kind != tokenEnd && p.peek().kind != tokenEOF {
+		p.expression(p.syntax.statementWords...)
+	}
+}
+
+// unparenthesize returns expression without the parentheses around it, if any.
+func unparenthesize(expression string) string {
+	runes := []rune(expression)
+	if len(runes) > 0 && runes[0] == '(' && closingParen(runes, 0) == len(runes)-1 {
+		return strings.TrimSpace(string(runes[1 : len(runes)-1]))
+	}
+	return expression
+}
+
+func contains(words []string, word string) bool {
+	for _, w := range words {
+		if strings.EqualFold(w, word) {
+			return true
+		}
+	}
+	return false
+}
+
+/////////////////////////////////////////////
+// create table /////////////////////////////
+/////////////////////////////////////////////
+
+// createTable reads CREATE [modifiers] TABLE [IF NOT EXISTS] name (definitions) options.
+func (p *ddlParser) createTable() func() {
+	start := p.peek().start
+	p.expect("CREATE")
+	for !p.accept("TABLE") {
+		if p.is("VIRTUAL") {
+			p.skip("")
+			return func() { p.warnf(start, "virtual table skipped") }
+		}
+		p.pos++
+	}
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	database, name := p.tableName()
+	if !p.is("(") {
+		// AS SELECT, PARTITION OF parent, OF type
+		p.skip("")
+		return func() { p.warnf(start, "can not infer columns of %s, skipped", name) }
+	}
+	t := &schema.Table{
+		Name:     name,
+		Database: database,
+		Dialect:  p.l.dialect(),
+		Source:   p.l.CurrentSource,
+	}
+	p.l.CurrentTable = t
+	defer func() { p.l.CurrentTable = nil }()
+	p.expect("(")
+	// a table may have no columns in PostgreSQL
+	for !p.is(")") {
+		p.definition(t)
+		if !p.accept(",") {
+			break
+		}
+		if p.is(")") {
+			p.errorf("mismatched input %s expecting a column", p.quote(p.peek()))
+		}
+	}
+	p.expect(")")
+	p.fe.tableOptions(p, t)
+	return func() { p.l.putTable(t, ifNotExists) }
+}
+
+// definition reads a column, a table constraint or an index of CREATE TABLE.
+func (p *ddlParser) definition(t *schema.Table) {
+	switch {
+	case p.isTableConstraint():
+		p.tableConstraint(t)
+	case p.is("LIKE"):
+		p.like(t)
+	case p.is("INDEX") && (p.at(1).kind == tokenWord || p.at(1).kind == tokenName) &&
+		contains([]string{"(", "UNIQUE", "CLUSTERED", "NONCLUSTERED"}, p.at(2).text):
+		// an index of T-SQL, INDEX name [UNIQUE] [CLUSTERED | NONCLUSTERED] (columns)
+		p.expect("INDEX")
+		idx := &schema.Index{Name: p.name(), Kind: schema.IndexNormal}
+		if p.accept("UNIQUE") {
+			idx.Kind = schema.IndexUnique
+		}
+		idx.Clustered = p.clustered()
+		idx.Columns = p.indexColumns()
+		p.rest()
+		p.l.addIndex(idx)
+	default:
+		col, keys := p.column()
+		t.Columns = append(t.Columns, col)
+		p.addKeys(col, keys)
+	}
+}
+
+// like reads LIKE table [INCLUDING | EXCLUDING what]... of PostgreSQL, which copies the
+// columns of table into t, and their defaults and the indexes if they are included.
+func (p *ddlParser) like(t *schema.Table) {
+	p.expect("LIKE")
+	start := p.peek().start
+	database, name := p.tableName()
+	var defaults, indexes bool
+	for {
+		including := p.accept("INCLUDING")
+		if !including && !p.accept("EXCLUDING") {
+			break
+		}
+		switch strings.ToUpper(p.name()) {
+		case "ALL":
+			defaults, indexes = including, including
+		case "DEFAULTS":
+			defaults = including
+		case "INDEXES":
+			indexes = including
+		}
+	}
+	src := p.l.Schema.Lookup(database, name)
+	if src == nil {
+		p.warnf(start, "create table %s like unknown table %s, skipped", t.Name, name)
+		return
+	}
+	src = src.Clone()
+	for _, col := range src.Columns {
+		if !defaults {
+			col.Default = nil
+		}
+		t.Columns = append(t.Columns, col)
+	}
+	if indexes {
+		t.Indexes = append(t.Indexes, src.Indexes...)
+	}
+}
+
+// isTableConstraint reports whether a table constraint is next.
+func (p *ddlParser) isTableConstraint() bool {
+	return p.is("CONSTRAINT") || p.is("PRIMARY", "KEY") || p.is("UNIQUE") ||
+		p.is("FOREIGN", "KEY") || p.is("CHECK") || p.is("EXCLUDE")
+}
+
+// tableConstraint reads [CONSTRAINT name] and a primary key, a unique key, a foreign key or a check,
+// or the default of a column of T-SQL, DEFAULT value FOR column. EXCLUDE constraints are skipped.
+func (p *ddlParser) tableConstraint(t *schema.Table) {
+	var name string
+	if p.accept("CONSTRAINT") {
+		name = p.name()
+	}
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		idx := &schema.Index{Name: name, Kind: schema.IndexPrimary, Clustered: p.clustered()}
+		idx.Columns = p.indexColumns()
+		p.rest()
+		if t.PrimaryKey() != nil {
+			p.errorf("multiple primary keys for table %s", t.Name)
+		}
+		p.l.addIndex(idx)
+		p.l.setPrimaryKey(idx.ColumnNames(), true)
+	case p.accept("UNIQUE"):
+		p.accept("KEY")
+		idx := &schema.Index{Name: name, Kind: schema.IndexUnique, Clustered: p.clustered()}
+		idx.Columns = p.indexColumns()
+		p.rest()
+		p.l.addIndex(idx)
+	case p.accept("FOREIGN", "KEY"):
+		fk := &schema.Constraint{
+			Name:    name,
+			Type:    schema.ConstraintForeignKey,
+			Columns: p.names(),
+		}
+		p.expect("REFERENCES")
+		p.reference(fk)
+		p.rest()
+		t.Constraints = append(t.Constraints, fk)
+	case p.accept("CHECK"):
+		t.Constraints = append(t.Constraints, &schema.Constraint{
+			Name:  name,
+			Type:  schema.ConstraintCheck,
+			Check: p.parenthesized(),
+		})
+		p.rest()
+	case p.accept("DEFAULT"):
+		value := p.expression("FOR")
+		p.expect("FOR")
+		column := p.name()
+		col := t.Column(column)
+		if col == nil {
+			p.errorf("unknown column %s in table %s", column, t.Name)
+		}
+		p.fe.setDefault(col, value)
+	case p.accept("EXCLUDE"):
+		p.rest()
+	default:
+		p.errorf("mismatched input %s expecting a constraint", p.quote(p.peek()))
+	}
+}
+
+// reference reads the table and the columns referenced by fk after REFERENCES, and its actions.
+func (p *ddlParser) reference(fk *schema.Constraint) {
+	_, fk.RefTable = p.tableName()
+	if p.is("(") {
+		fk.RefColumns = p.names()
+	}
+	for {
+		switch {
+		case p.accept("ON", "DELETE"):
+			fk.OnDelete = p.referenceAction()
+		case p.accept("ON", "UPDATE"):
+			fk.OnUpdate = p.referenceAction()
+		case p.accept("MATCH"), p.accept("INITIALLY"):
+			p.name()
+		case p.accept("DEFERRABLE"), p.accept("NOT", "DEFERRABLE"), p.accept("NOT", "FOR", "REPLICATION"):
+		default:
+			return
+		}
+	}
+}
+
+func (p *ddlParser) referenceAction() string {
+	for _, action := range [][]string{{"CASCADE"}, {"RESTRICT"}, {"NO", "ACTION"}, {"SET", "NULL"}, {"SET", "DEFAULT"}} {
+		if p.accept(action...) {
+			return strings.Join(action, " ")
+		}
+	}
+	p.errorf("mismatched input %s expecting a reference action", p.quote(p.peek()))
+	return ""
+}
+
+// clustered reads CLUSTERED or NONCLUSTERED of a key of T-SQL, empty if there is none.
+func (p *ddlParser) clustered() string {
+	for _, w := range []string{"CLUSTERED", "NONCLUSTERED"} {
+		if p.accept(w) {
+			return w
+		}
+	}
+	return ""
+}
+
+// indexColumns reads the columns of a key or an index in parentheses, a key part
+// which is not a column name is a functional key part, e.g. lower(email).
+// Collations, operator classes and NULLS FIRST or LAST are skipped.
+func (p *ddlParser) indexColumns() []*schema.IndexColumn {
+	p.expect("(")
+	var columns []*schema.IndexColumn
+	for {
+		col := &schema.IndexColumn{}
+		if next := p.at(1); p.isName() && (next.kind == tokenWord || next.kind == tokenName ||
+			next.text == "," || next.text == ")") {
+			col.Name = p.name()
+		} else {
+			col.Expression = unparenthesize(p.expression("ASC", "DESC", "NULLS", "COLLATE"))
+		}
+		if p.accept("COLLATE") {
+			p.qualifiedName()
+		}
+		if p.isName() && !p.is("ASC") && !p.is("DESC") && !p.is("NULLS") {
+			// the operator class of PostgreSQL, e.g. text_pattern_ops
+			p.qualifiedName()
+		}
+		col.Desc = p.accept("DESC")
+		if !col.Desc {
+			p.accept("ASC")
+		}
+		if p.accept("NULLS") {
+			p.name()
+		}
+		columns = append(columns, col)
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect(")")
+	return columns
+}
+
+/////////////////////////////////////////////
+// column ///////////////////////////////////
+/////////////////////////////////////////////
+
+// column reads the name, the type and the constraints of a column, the keys it declares
+// are returned to be added once the column is, see addKeys.
+func (p *ddlParser) column() (*schema.Column, []*schema.Index) {
+	col := &schema.Column{
+		Name:     p.name(),
+		Nullable: true,
+	}
+	p.l.CurrentCol = col
+	defer func() { p.l.CurrentCol = nil }()
+	if !p.is("AS") {
+		p.fe.setType(p, col, p.sqlType())
+	}
+	return col, p.columnConstraints(col)
+}
+
+// addKeys adds the keys declared by the constraints of col to the current table,
+// e.g. id bigint PRIMARY KEY.
+func (p *ddlParser) addKeys(col *schema.Column, keys []*schema.Index) {
+	for _, idx := range keys {
+		if idx.Kind == schema.IndexPrimary && p.l.CurrentTable.PrimaryKey() != nil {
+			p.errorf("multiple primary keys for table %s", p.l.CurrentTable.Name)
+		}
+		idx.Columns[0].Name = col.Name
+		p.l.addIndex(idx)
+	}
+}
+
+// columnConstraints reads the constraints of col, a key is returned for each
+// PRIMARY KEY and UNIQUE, foreign keys and checks are added to the current table.
+func (p *ddlParser) columnConstraints(col *schema.Column) []*schema.Index {
+	var keys []*schema.Index
+	for {
+		var name string
+		if p.accept("CONSTRAINT") {
+			name = p.name()
+		}
+		switch {
+		case p.accept("NOT", "NULL"):
+			col.Nullable = false
+		case p.accept("NULL"):
+			col.Nullable = true
+		case p.accept("DEFAULT"):
+			p.fe.setDefault(col, p.expression(p.columnWords()...))
+		case p.accept("PRIMARY", "KEY"):
+			col.PrimaryKey = true
+			col.Nullable = false
+			key := &schema.IndexColumn{Desc: p.accept("DESC")}
+			if !key.Desc {
+				p.accept("ASC")
+			}
+			keys = append(keys, &schema.Index{
+				Name:      name,
+				Kind:      schema.IndexPrimary,
+				Columns:   []*schema.IndexColumn{key},
+				Clustered: p.clustered(),
+			})
+		case p.accept("UNIQUE"):
+			col.Unique = true
+			keys = append(keys, &schema.Index{
+				Name:      name,
+				Kind:      schema.IndexUnique,
+				Columns:   []*schema.IndexColumn{{}},
+				Clustered: p.clustered(),
+			})
+		case p.accept("REFERENCES"):
+			fk := &schema.Constraint{
+				Name:    name,
+				Type:    schema.ConstraintForeignKey,
+				Columns: []string{col.Name},
+			}
+			p.reference(fk)
+			p.l.CurrentTable.Constraints = append(p.l.CurrentTable.Constraints, fk)
+		case p.accept("CHECK"):
+			p.l.CurrentTable.Constraints = append(p.l.CurrentTable.Constraints, &schema.Constraint{
+				Name:    name,
+				Type:    schema.ConstraintCheck,
+				Columns: []string{col.Name},
+				Check:   p.parenthesized(),
+			})
+		case p.accept("COLLATE"):
+			col.Collation = p.name()
+		case p.accept("GENERATED"):
+			if !p.accept("ALWAYS") {
+				p.expect("BY", "DEFAULT")
+			}
+			p.expect("AS")
+			if p.accept("IDENTITY") {
+				col.AutoIncrement = true
+				col.Nullable = false
+				if p.is("(") {
+					p.parenthesized()
+				}
+				break
+			}
+			col.Generated = p.parenthesized()
+			col.Stored = p.accept("STORED")
+			p.accept("VIRTUAL")
+		case p.accept("AS"):
+			// a generated column of SQLite, or a computed column of T-SQL without parentheses
+			col.Generated = unparenthesize(p.expression(append(p.columnWords(), "STORED", "PERSISTED", "VIRTUAL")...))
+			col.Stored = p.accept("STORED") || p.accept("PERSISTED")
+			p.accept("VIRTUAL")
+		case p.fe.columnConstraint(p, col):
+		default:
+			if name != "" {
+				p.errorf("mismatched input %s expecting a constraint", p.quote(p.peek()))
+			}
+			return keys
+		}
+	}
+}
+
+// columnWords are the words starting the column constraints of the dialect, or a statement.
+func (p *ddlParser) columnWords() []string {
+	words := append(append([]string(nil), columnWords...), p.syntax.columnWords...)
+	return append(words, p.syntax.statementWords...)
+}
+
+// sqlType is a type as written.
+type sqlType struct {
+	// name is the lower case name, words are joined by a space, e.g. double precision.
+	name string
+	// args are the lower case arguments in parentheses, e.g. 10 and 2 of numeric(10,2).
+	args []string
+	// array is set for an array, e.g. text[].
+	array bool
+	raw   string
+}
+
+// length returns the argument i as a number, nil if there is none.
+func (t sqlType) length(i int) *int {
+	if i >= len(t.args) {
+		return nil
+	}
+	return atoi(t.args[i])
+}
+
+// sqlType reads a type: its names up to a word starting a column constraint, its arguments
+// in parentheses and the [] of an array. It is empty if the column has no type, like SQLite allows.
+func (p *ddlParser) sqlType() sqlType {
+	start := p.pos
+	var t sqlType
+	var words []string
+	stop := p.columnWords()
+loop:
+	for {
+		next := p.peek()
+		switch {
+		case next.kind == tokenWord && !contains(stop, next.text) || next.kind == tokenName:
+			name := strings.ToLower(p.name())
+			for p.accept(".") {
+				// a schema-qualified type, e.g. public.citext
+				name = strings.ToLower(p.name())
+			}
+			if name == "array" {
+				t.array = true
+				if p.accept("[") {
+					if p.peek().kind == tokenNumber {
+						p.pos++
+					}
+					p.expect("]")
+				}
+				continue
+			}
+			words = append(words, name)
+		case len(words) > 0 && t.args == nil && p.is("("):
+			p.pos++
+			t.args = []string{}
+			for !p.is(")") {
+				t.args = append(t.args, strings.ToLower(p.expression()))
+				if !p.accept(",") {
+					break
+				}
+			}
+			p.expect(")")
+		case len(words) > 0 && p.accept("["):
+			if p.peek().kind == tokenNumber {
+				p.pos++
+			}
+			p.expect("]")
+			t.array = true
+		default:
+			break loop
+		}
+	}
+	t.name = strings.Join(words, " ")
+	if p.pos > start {
+		t.raw = p.text(start, p.pos)
+	}
+	return t
+}
+
+/////////////////////////////////////////////
+// create index /////////////////////////////
+/////////////////////////////////////////////
+
+// createIndex reads CREATE [UNIQUE] [CLUSTERED | NONCLUSTERED] INDEX [CONCURRENTLY]
+// [IF NOT EXISTS] [name] ON table [USING method] (columns), storage clauses and the
+// WHERE of a partial index are skipped.
+func (p *ddlParser) createIndex() func() {
+	start := p.peek().start
+	p.expect("CREATE")
+	idx := &schema.Index{Kind: schema.IndexNormal}
+	if p.accept("UNIQUE") {
+		idx.Kind = schema.IndexUnique
+	}
+	idx.Clustered = p.clustered()
+	p.expect("INDEX")
+	p.accept("CONCURRENTLY")
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	if !p.is("ON") {
+		// the index of SQLite may be schema-qualified
+		parts := p.qualifiedName()
+		idx.Name = parts[len(parts)-1]
+	}
+	p.expect("ON")
+	p.accept("ONLY")
+	database, name := p.tableName()
+	if p.accept("USING") {
+		idx.Using = strings.ToUpper(p.name())
+	}
+	idx.Columns = p.indexColumns()
+	p.skip("")
+	return func() {
+		t := p.l.Schema.Lookup(database, name)
+		if t == nil {
+			p.warnf(start, "create index on unknown table %s, skipped", name)
+			return
+		}
+		if ifNotExists && idx.Name != "" && t.Index(idx.Name) != nil {
+			return
+		}
+		p.l.CurrentTable = t
+		p.l.addIndex(idx)
+		p.l.CurrentTable = nil
+	}
+}
+
+/////////////////////////////////////////////
+// drop /////////////////////////////////////
+/////////////////////////////////////////////
+
+// dropTable reads DROP TABLE [IF EXISTS] names [CASCADE | RESTRICT].
+func (p *ddlParser) dropTable() func() {
+	start := p.peek().start
+	p.expect("DROP", "TABLE")
+	ifExists := p.accept("IF", "EXISTS")
+	type table struct{ database, name string }
+	var tables []table
+	for {
+		database, name := p.tableName()
+		tables = append(tables, table{database, name})
+		if !p.accept(",") {
+			break
+		}
+	}
+	if !p.accept("CASCADE") {
+		p.accept("RESTRICT")
+	}
+	return func() {
+		for _, t := range tables {
+			if p.l.Schema.Remove(t.database, t.name) == nil && !ifExists {
+				p.warnf(start, "drop unknown table %s", t.name)
+			}
+		}
+	}
+}
+
+// dropIndex reads DROP INDEX [CONCURRENTLY] [IF EXISTS] names [ON table] [CASCADE | RESTRICT].
+// Without ON, the index is looked up in all tables, the qualifier of a name is the schema.
+func (p *ddlParser) dropIndex() func() {
+	start := p.peek().start
+	p.expect("DROP", "INDEX")
+	p.accept("CONCURRENTLY")
+	ifExists := p.accept("IF", "EXISTS")
+	var names [][]string
+	for {
+		names = append(names, p.qualifiedName())
+		if !p.accept(",") {
+			break
+		}
+	}
+	var database, table string
+	if p.accept("ON") {
+		database, table = p.tableName()
+	}
+	if !p.accept("CASCADE") {
+		p.accept("RESTRICT")
+	}
+	return func() {
+		for _, parts := range names {
+			name := parts[len(parts)-1]
+			var t *schema.Table
+			if table != "" {
+				t = p.l.Schema.Lookup(database, table)
+			} else {
+				t = p.l.indexTable(strings.Join(parts[:len(parts)-1], "."), name)
+			}
+			if t == nil || removeIndex(t, name) == nil {
+				if !ifExists {
+					p.warnf(start, "drop unknown index %s, skipped", name)
+				}
+			}
+		}
+	}
+}
+
+// indexTable returns the table of database with the index name, nil if there is none.
+func (l *Listener) indexTable(database, name string) *schema.Table {
+	for _, t := range l.Schema.Tables {
+		if database != "" && !strings.EqualFold(t.Database, database) {
+			continue
+		}
+		if t.Index(name) != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+/////////////////////////////////////////////
+// alter table //////////////////////////////
+/////////////////////////////////////////////
+
+// alterTable reads ALTER TABLE [IF EXISTS] [ONLY] name actions. The actions are applied
+// to a copy of the table, which replaces it once the statement is read.
+func (p *ddlParser) alterTable() func() {
+	start := p.peek().start
+	p.expect("ALTER", "TABLE")
+	ifExists := p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+	database, name := p.tableName()
+	p.accept("*")
+	old := p.l.Schema.Lookup(database, name)
+	if old == nil {
+		p.skip("")
+		return func() {
+			if !ifExists {
+				p.warnf(start, "alter unknown table %s, skipped", name)
+			}
+		}
+	}
+	t := old.Clone()
+	p.l.CurrentTable = t
+	defer func() { p.l.CurrentTable = nil }()
+	action := ""
+	for {
+		action = p.alterAction(old, t, action)
+		if !p.accept(",") {
+			break
+		}
+	}
+	return func() { *old = *t }
+}
+
+// alterAction reads an action of ALTER TABLE and applies it to t, a copy of old.
+// last is the previous action, T-SQL repeats it without its keyword, e.g. ADD a int, b int.
+// Actions which do not change the model, e.g. OWNER TO, are skipped.
+func (p *ddlParser) alterAction(old, t *schema.Table, last string) string {
+	if !p.accept("WITH", "CHECK") {
+		p.accept("WITH", "NOCHECK")
+	}
+	action := strings.ToUpper(p.peek().text)
+	switch {
+	case p.accept("ADD"), p.accept("DROP"), p.accept("ALTER"), p.accept("RENAME"):
+	case last != "" && p.syntax.batches:
+		action = last
+	default:
+		p.expression(p.syntax.statementWords...)
+		return ""
+	}
+
+	switch action {
+	case "ADD":
+		if p.isTableConstraint() || p.is("DEFAULT") {
+			p.tableConstraint(t)
+			break
+		}
+		p.accept("COLUMN")
+		ifNotExists := p.accept("IF", "NOT", "EXISTS")
+		start := p.pos
+		col, keys := p.column()
+		if t.Column(col.Name) != nil {
+			if ifNotExists {
+				break
+			}
+			p.pos = start
+			p.errorf("add column %s: already exists in table %s", col.Name, t.Name)
+		}
+		t.AddColumn(col, false, "")
+		p.addKeys(col, keys)
+	case "DROP":
+		if p.accept("CONSTRAINT") {
+			ifExists := p.accept("IF", "EXISTS")
+			name := p.name()
+			if !dropConstraint(t, name) && !ifExists {
+				p.pos--
+				p.errorf("unknown constraint %s in table %s", name, t.Name)
+			}
+		} else {
+			p.accept("COLUMN")
+			ifExists := p.accept("IF", "EXISTS")
+			name := p.name()
+			if t.DropColumn(name) == nil && !ifExists {
+				p.pos--
+				p.errorf("unknown column %s in table %s", name, t.Name)
+			}
+		}
+		if !p.accept("CASCADE") {
+			p.accept("RESTRICT")
+		}
+	case "ALTER":
+		p.accept("COLUMN")
+		name := p.name()
+		col := t.Column(name)
+		if col == nil {
+			p.pos--
+			p.errorf("unknown column %s in table %s", name, t.Name)
+		}
+		p.alterColumn(col)
+	case "RENAME":
+		switch {
+		case p.accept("TO"):
+			_, to := p.tableName()
+			if other := p.l.Schema.Lookup(t.Database, to); other != nil && other != old {
+				p.pos--
+				p.errorf("rename table %s to %s: already exists", t.Name, to)
+			}
+			t.Name = to
+		case p.accept("CONSTRAINT"):
+			from := p.name()
+			p.expect("TO")
+			to := p.name()
+			if c := t.Constraint(from); c != nil {
+				c.Name = to
+			} else if idx := t.Index(from); idx != nil {
+				idx.Name = to
+			} else {
+				p.errorf("unknown constraint %s in table %s", from, t.Name)
+			}
+		default:
+			p.accept("COLUMN")
+			from := p.name()
+			p.expect("TO")
+			to := p.name()
+			if t.RenameColumn(from, to) == nil {
+				p.errorf("unknown column %s in table %s", from, t.Name)
+			}
+		}
+	}
+	return action
+}
+
+// alterColumn reads ALTER COLUMN name followed by SET DEFAULT, DROP DEFAULT, SET NOT NULL,
+// DROP NOT NULL or [SET DATA] TYPE, or by the new type and nullability of the column in T-SQL.
+func (p *ddlParser) alterColumn(col *schema.Column) {
+	switch {
+	case p.accept("SET", "DEFAULT"):
+		p.fe.setDefault(col, p.expression())
+	case p.accept("DROP", "DEFAULT"):
+		col.Default = nil
+	case p.accept("SET", "NOT", "NULL"):
+		col.Nullable = false
+	case p.accept("DROP", "NOT", "NULL"):
+		col.Nullable = true
+	case p.accept("TYPE"), p.accept("SET", "DATA", "TYPE"):
+		p.fe.setType(p, col, p.sqlType())
+		if p.accept("COLLATE") {
+			col.Collation = p.name()
+		}
+		if p.accept("USING") {
+			p.expression()
+		}
+	case p.accept("ADD", "GENERATED"):
+		col.AutoIncrement = true
+		p.expression()
+	case p.accept("DROP", "IDENTITY"):
+		col.AutoIncrement = false
+		p.accept("IF", "EXISTS")
+	case p.is("SET"), p.is("DROP"), p.is("RESET"):
+		// SET STATISTICS, SET STORAGE and the like
+		p.expression()
+	default:
+		p.fe.setType(p, col, p.sqlType())
+		col.Nullable = true
+		for {
+			switch {
+			case p.accept("COLLATE"):
+				col.Collation = p.name()
+			case p.accept("NOT", "NULL"):
+				col.Nullable = false
+			case p.accept("NULL"):
+				col.Nullable = true
+			default:
+				return
+			}
+		}
+	}
+}
+
+// dropConstraint removes the constraint or the key name of t, false if there is none.
+func dropConstraint(t *schema.Table, name string) bool {
+	if t.DropConstraint(name) != nil {
+		return true
+	}
+	idx := removeIndex(t, name)
+	if idx == nil {
+		return false
+	}
+	if idx.Kind == schema.IndexPrimary {
+		for _, column := range idx.ColumnNames() {
+			if col := t.Column(column); col != nil {
+				col.PrimaryKey = false
+			}
+		}
+	}
+	return true
+}
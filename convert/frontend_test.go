@@ -0,0 +1,74 @@
+package convert
+
+import "testing"
+
+func TestParseDDLPosition(t *testing.T) {
+	script := "DO $$ BEGIN RAISE NOTICE 'é;'; END $$;\nCREATE TABLE a (x int);\n" +
+		"CREATE TABLE b (y int,);\nALTER TABLE a ADD COLUMN z text[];"
+	l := NewListener(Option{Dialect: DialectPostgres})
+	err := l.Parse("x.sql", script)
+	list, ok := err.(ErrorList)
+	if !ok || len(list) != 1 {
+		t.Fatalf("Parse = %v, want one error", err)
+	}
+	e, ok := list[0].(*SyntaxError)
+	if !ok || e.Line != 3 || e.Column != 22 {
+		t.Errorf("error = %v, want at 3:22", list[0])
+	}
+	if a := l.Schema.Table("a"); a == nil || a.Column("z") == nil || a.Column("z").Type.Name != "text[]" {
+		t.Errorf("statements after an error are not applied")
+	}
+}
+
+func TestWithoutCast(t *testing.T) {
+	tests := map[string]string{
+		"'a'::character varying":      "'a'",
+		"'{}'::text[]":                "'{}'",
+		"'it''s'::text":               "'it''s'",
+		"(0)::numeric(10,2)":          "(0)",
+		"NULL::character varying":     "NULL",
+		"now()":                       "now()",
+		"(now() + '1 day'::interval)": "(now() + '1 day'::interval)",
+		"'a'::text || 'b'::text":      "'a'::text || 'b'::text",
+	}
+	for value, want := range tests {
+		if got := withoutCast(value); got != want {
+			t.Errorf("withoutCast(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestSQLiteErrors(t *testing.T) {
+	tests := map[string]string{
+		"CREATE TABLE t (id INT PRIMARY KEY AUTOINCREMENT);":                   "AUTOINCREMENT is only allowed on an INTEGER PRIMARY KEY",
+		"CREATE TABLE t (id INTEGER PRIMARY KEY AUTOINCREMENT) WITHOUT ROWID;": "AUTOINCREMENT not allowed on WITHOUT ROWID tables",
+		"CREATE TABLE t (id INTEGER) WITHOUT ROWID;":                           "PRIMARY KEY missing on table t",
+		"CREATE TABLE t (id INTEGER, at DATETIME) STRICT;":                     `unknown datatype for t.at: "DATETIME"`,
+		"CREATE TABLE t (id INTEGER) TEMPORARY;":                               "unknown table option: TEMPORARY",
+	}
+	for script, want := range tests {
+		err := NewListener(Option{Dialect: DialectSQLite}).Parse("x.sql", script)
+		list, ok := err.(ErrorList)
+		if !ok || len(list) != 1 || list[0].(*SyntaxError).Msg != want {
+			t.Errorf("Parse(%q) = %v, want %s", script, err, want)
+		}
+	}
+}
+
+func TestTSQLRecovery(t *testing.T) {
+	script := "CREATE TABLE a (x int IDENTITY(1,1))\nCREATE TABLE b (y int,)\n" +
+		"CREATE LOGIN reader WITH PASSWORD = 'x'\nALTER TABLE a ADD z nvarchar(max)\nGO"
+	l := NewListener(Option{Dialect: DialectTSQL})
+	err := l.Parse("x.sql", script)
+	list, ok := err.(ErrorList)
+	if !ok || len(list) != 1 {
+		t.Fatalf("Parse = %v, want one error", err)
+	}
+	if e := list[0].(*SyntaxError); e.Line != 2 || e.Column != 22 {
+		t.Errorf("error = %v, want at 2:22", e)
+	}
+	a := l.Schema.Table("a")
+	if a == nil || !a.Column("x").AutoIncrement || a.Column("z") == nil || a.Column("z").Type.Name != "longtext" {
+		t.Errorf("statements after an error are not applied")
+	}
+}
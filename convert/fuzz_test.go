@@ -0,0 +1,68 @@
+package convert_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+)
+
+// FuzzConvert parses and renders any input of any dialect, the index of Dialects,
+// the converter must not panic and the models must be valid go. Seeds are the DDL
+// statements of the examples and the scripts of the dialects,
+// run with go test ./convert -run '^$' -fuzz FuzzConvert.
+func FuzzConvert(f *testing.F) {
+	paths, err := filepath.Glob("../antlr4_gen/examples/*.sql")
+	if err != nil {
+		f.Fatal(err)
+	}
+	paths = append(paths, "testdata/mysqldump.sql", "testdata/identifiers.sql")
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		for _, s := range convert.Split(string(content)) {
+			switch s.Keyword() {
+			case "CREATE", "ALTER", "DROP", "RENAME":
+				f.Add(s.Text+";", uint8(0))
+			}
+		}
+	}
+
+	// the front ends split the scripts of their dialect themselves
+	for i, dialect := range convert.Dialects[1:] {
+		content, err := os.ReadFile(filepath.Join("testdata", dialect, dialect+".sql"))
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(content), uint8(i+1))
+	}
+
+	// names, comments and defaults which do not fit in a raw string or a comment
+	f.Add("CREATE TABLE `t\x00` (`a``b` int COMMENT 'x\r\n`y\ufeff', `c` char(1) DEFAULT '`');", uint8(0))
+	f.Add("CREATE TABLE t (a int) COMMENT '\xff'; ALTER TABLE t ADD INDEX (;", uint8(0))
+
+	f.Fuzz(func(t *testing.T, sql string, dialect uint8) {
+		option := convert.DefaultOption()
+		option.Warnf = func(string, ...interface{}) {}
+		option.Dialect = convert.Dialects[int(dialect)%len(convert.Dialects)]
+		converter := convert.NewConverter(option)
+		// the tables parsed before an error are rendered too
+		_ = converter.Add("fuzz.sql", strings.NewReader(sql))
+		convert.CheckReferences(converter.Schema(), option)
+
+		buf := new(bytes.Buffer)
+		if err := convert.Render(converter.Schema(), buf, option); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "models.go", buf.Bytes(), 0); err != nil {
+			t.Fatalf("invalid models: %s\n%s", err, buf.String())
+		}
+	})
+}
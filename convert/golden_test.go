@@ -0,0 +1,201 @@
+package convert_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// goldenCase converts sql in dialect into the models of golden.
+type goldenCase struct {
+	sql     string
+	golden  string
+	dialect string
+}
+
+func goldenCases(t *testing.T) []goldenCase {
+	cases := make([]goldenCase, 0)
+	add := func(pattern, dir, dialect string) {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range paths {
+			name := strings.TrimSuffix(filepath.Base(path), ".sql")
+			cases = append(cases, goldenCase{sql: path, golden: filepath.Join(dir, name+".golden.go"), dialect: dialect})
+		}
+	}
+	// the examples of the grammar and real-world ddl
+	add("../antlr4_gen/examples/*.sql", "testdata/examples", "")
+	add("testdata/*.sql", "testdata", "")
+	// the ddl of other dialects is in a directory named after them
+	for _, dialect := range convert.Dialects[1:] {
+		add(filepath.Join("testdata", dialect, "*.sql"), filepath.Join("testdata", dialect), dialect)
+	}
+	return cases
+}
+
+// TestGolden converts each case like the command does and compares the models
+// and the warnings with the golden file, run with -update to write them.
+// The models must type-check.
+func TestGolden(t *testing.T) {
+	for _, c := range goldenCases(t) {
+		c := c
+		t.Run(filepath.Base(c.sql), func(t *testing.T) {
+			got := convertFile(t, c.sql, c.dialect)
+			typeCheck(t, got)
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(c.golden), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(c.golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(c.golden)
+			if err != nil {
+				t.Fatalf("%s, run go test -update to write it", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("output differs from %s, run go test -update and review the diff, got:\n%s", c.golden, got)
+			}
+		})
+	}
+}
+
+// convertFile returns the models of path in dialect, followed by the warnings as comments.
+func convertFile(t *testing.T, path, dialect string) []byte {
+	var warnings []string
+	option := convert.DefaultOption()
+	option.Dialect = dialect
+	option.Warnf = func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	converter := convert.NewConverter(option)
+	if err := converter.Add(filepath.Base(path), f); err != nil {
+		t.Fatal(err)
+	}
+	convert.CheckReferences(converter.Schema(), option)
+
+	buf := new(bytes.Buffer)
+	if err := convert.Render(converter.Schema(), buf, option); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) > 0 {
+		buf.WriteString("// Warnings:\n")
+		for _, w := range warnings {
+			buf.WriteString("// " + strings.ReplaceAll(w, "\n", "\n// ") + "\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+var (
+	fset = token.NewFileSet()
+	// imports parses the imported packages from source once for all cases.
+	imports = importer.ForCompiler(fset, "source", nil)
+)
+
+// typeCheck fails t if src is not a valid go package.
+func typeCheck(t *testing.T, src []byte) {
+	t.Helper()
+	file, err := parser.ParseFile(fset, "models.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse models: %s", err)
+	}
+	conf := types.Config{Importer: imports}
+	if _, err := conf.Check("models", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("type-check models: %s", err)
+	}
+}
+
+// dialectTypes are the type names of the dialects, without their arguments, and the types
+// created by the golden cases, the models of a dialect are migrated by gorm with these.
+var dialectTypes = map[string][]string{
+	convert.DialectPostgres: {
+		"smallint", "integer", "int", "int2", "int4", "int8", "bigint",
+		"smallserial", "serial", "bigserial", "serial2", "serial4", "serial8",
+		"numeric", "decimal", "real", "float", "float4", "float8", "double precision", "money",
+		"boolean", "bool", "text", "varchar", "character varying", "char", "character", "bpchar", `"char"`,
+		"bytea", "uuid", "json", "jsonb", "xml", "inet", "cidr", "macaddr", "bit", "varbit", "bit varying",
+		"date", "time", "timetz", "timestamp", "timestamptz", "time with time zone", "time without time zone",
+		"timestamp with time zone", "timestamp without time zone",
+		"interval", "interval year to month", "interval day to second", "interval hour to minute",
+		"public.order_status", "public.email",
+	},
+	convert.DialectTSQL: {
+		"tinyint", "smallint", "int", "bigint", "bit", "decimal", "numeric", "money", "smallmoney",
+		"float", "real", "date", "time", "datetime", "datetime2", "smalldatetime", "datetimeoffset",
+		"char", "varchar", "text", "nchar", "nvarchar", "ntext", "binary", "varbinary", "image",
+		"uniqueidentifier", "timestamp", "rowversion", "xml", "sql_variant", "sysname",
+		"dbo.phone",
+	},
+}
+
+// TestDialectTypes checks that the type tags of the models of a dialect name its types,
+// not those of mysql, e.g. timestamp(3) without time zone, not datetime(3).
+// Computed columns of SQL Server have no type, nor a type tag.
+func TestDialectTypes(t *testing.T) {
+	for dialect, names := range dialectTypes {
+		valid := make(map[string]bool, len(names))
+		for _, name := range names {
+			valid[name] = true
+		}
+		golden := filepath.Join("testdata", dialect, dialect+".golden.go")
+		file, err := parser.ParseFile(token.NewFileSet(), golden, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			field, ok := n.(*ast.Field)
+			if !ok || field.Tag == nil {
+				return true
+			}
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, setting := range strings.Split(reflect.StructTag(tag).Get("gorm"), ";") {
+				if value, ok := strings.CutPrefix(setting, "type:"); ok && !valid[typeName(dialect, value)] {
+					t.Errorf("%s: field %s has type %s, not a type of %s", golden, field.Names[0].Name, value, dialect)
+				}
+			}
+			return false
+		})
+	}
+}
+
+// typeArgs are the arguments of a type, e.g. (10, 2).
+var typeArgs = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// typeName returns the lower case name of a type of dialect without its arguments and array brackets.
+func typeName(dialect, value string) string {
+	name := typeArgs.ReplaceAllString(strings.ToLower(value), "")
+	if dialect == convert.DialectTSQL {
+		name = strings.NewReplacer("[", "", "]", "").Replace(name)
+	}
+	name = strings.ReplaceAll(name, "[]", "")
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,780 @@
+package convert
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	gen "github.com/er1c-zh/sql-to-gorm/antlr4_gen"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+type Listener struct {
+	*gen.BaseMySqlParserListener
+	CurrentTable *schema.Table
+	CurrentCol   *schema.Column
+	// CurrentSource is the name of the input being walked.
+	CurrentSource string
+	// Database is the current database set by USE, it qualifies the tables
+	// created and looked up without database.
+	Database      string
+	errorListener *ErrorListener
+	option        Option
+	// parserPool are the parsers created by Parse, reused by the next ones.
+	parserPool []*parser
+	// statements are the kinds of statements parsed
+	statements map[string]bool
+	// rewrites are those of the statement walked, see rewrite8
+	rewrites []rewrite
+	// types are the types created by the DDL, e.g. the enums of PostgreSQL, by lower case name.
+	types map[string]schema.DataType
+
+	Schema *schema.Schema
+}
+
+func NewListener(option Option) *Listener {
+	kinds := option.Statements
+	if kinds == nil {
+		kinds = DefaultStatements
+	}
+	statements := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		statements[strings.ToUpper(strings.Join(strings.Fields(kind), " "))] = true
+	}
+	return &Listener{
+		Schema:     &schema.Schema{},
+		option:     option,
+		statements: statements,
+		types:      make(map[string]schema.DataType),
+	}
+}
+
+// Parse walks all statements of content, adding the tables to the listener.
+// Statements are parsed concurrently, see Option.Jobs, and walked in order.
+// Only the statements of the kinds in Option.Statements are parsed, the others,
+// like INSERT, SELECT and routines, are skipped, see Split and Statement.Kind.
+// MySQL 8.0 DDL the grammar does not know is accepted too, and so is the DDL
+// of Option.Dialect, see rewriteDialect. The dialects which are not MySQL compatible
+// are read by their frontend instead, statement by statement, see frontend.
+// Syntax errors and inconsistent parse trees are reported with their position,
+// a statement with errors is left out and the others are still applied.
+func (l *Listener) Parse(source string, content string) error {
+	dialect := l.option.Dialect
+	if dialect == "" {
+		dialect = DialectMySQL
+	}
+	if !validDialect(dialect) {
+		return fmt.Errorf("unknown dialect %s, want one of %s", dialect, strings.Join(Dialects, ", "))
+	}
+	errorListener := NewErrorListener(source, content)
+
+	l.CurrentSource = source
+	l.errorListener = errorListener
+	defer func() {
+		l.CurrentSource = ""
+		l.Database = ""
+		l.errorListener = nil
+		l.CurrentTable = nil
+		l.CurrentCol = nil
+		l.rewrites = nil
+	}()
+
+	if fe, ok := frontends[dialect]; ok {
+		l.parseDDL(fe, content)
+		return errorListener.Errors.Err()
+	}
+	statements := make([]Statement, 0)
+	for _, s := range Split(content) {
+		if l.parses(s) {
+			statements = append(statements, s)
+		}
+	}
+	for _, s := range parseAll(l.parsers(len(statements)), content, statements, dialect) {
+		for _, e := range s.errors {
+			errorListener.Errors = append(errorListener.Errors, errorListener.NewError(e.Line, e.Column, e.Msg))
+		}
+		// a statement with errors is not applied, the others are
+		if len(s.errors) == 0 {
+			l.rewrites = s.rewrites
+			l.walk(s.tree, s.pos)
+		}
+	}
+
+	return errorListener.Errors.Err()
+}
+
+// dialect returns the dialect of the tables created, empty for mysql.
+func (l *Listener) dialect() string {
+	if l.option.Dialect == DialectMySQL {
+		return ""
+	}
+	return l.option.Dialect
+}
+
+// parses reports whether s is parsed: it is of a kind in option.Statements
+// and ends with `;`, those ending with a delimiter set by DELIMITER are triggers and routines.
+func (l *Listener) parses(s Statement) bool {
+	return (s.Delimiter == ";" || s.Delimiter == "") && l.statements[s.Kind()]
+}
+
+// walk walks the tree of a statement starting at pos, the tree of a malformed
+// statement may have a shape the listener does not expect, it is reported instead of crashing.
+func (l *Listener) walk(tree gen.IRootContext, pos position) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.errorListener.Errors = append(l.errorListener.Errors,
+				l.errorListener.NewError(pos.line, pos.column, fmt.Sprintf("internal error: %v", r)))
+			l.CurrentTable = nil
+			l.CurrentCol = nil
+		}
+	}()
+	antlr.ParseTreeWalkerDefault.Walk(l, tree)
+}
+
+// parsers returns the parsers to parse n statements with, at most option.Jobs,
+// they are created when first needed and reused by the next inputs.
+func (l *Listener) parsers(n int) []*parser {
+	jobs := l.option.Jobs
+	if jobs <= 0 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+	if n > jobs {
+		n = jobs
+	}
+	for len(l.parserPool) < n {
+		l.parserPool = append(l.parserPool, newParser())
+	}
+	return l.parserPool[:n]
+}
+
+// Error reports an error at the start of ctx.
+func (l *Listener) Error(ctx antlr.ParserRuleContext, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if l.errorListener == nil {
+		l.option.warnf("%s", msg)
+		return
+	}
+	start := ctx.GetStart()
+	l.errorListener.Errors = append(l.errorListener.Errors,
+		l.errorListener.NewError(start.GetLine(), start.GetColumn(), msg))
+}
+
+func (l *Listener) EnterColumnCreateTable(ctx *gen.ColumnCreateTableContext) {
+	if l.CurrentTable != nil {
+		l.Error(ctx, "table %s is not done", l.CurrentTable.Name)
+	}
+
+	database, name := l.tableName(ctx.TableName())
+	l.CurrentTable = &schema.Table{
+		Name:     name,
+		Database: database,
+		Dialect:  l.dialect(),
+		Source:   l.CurrentSource,
+	}
+	for _, option := range ctx.AllTableOption() {
+		l.addTableOption(option)
+	}
+}
+func (l *Listener) ExitColumnCreateTable(ctx *gen.ColumnCreateTableContext) {
+	if l.CurrentTable == nil {
+		l.Error(ctx, "table done but not started")
+		return
+	}
+	l.setDialectTableOptions(ctx)
+	l.putTable(l.CurrentTable, ctx.IfNotExists() != nil)
+	l.CurrentTable = nil
+}
+
+func (l *Listener) addTableOption(ctx gen.ITableOptionContext) {
+	text := originalText(ctx)
+	name, value := text, ""
+	switch c := ctx.(type) {
+	case *gen.TableOptionCommentContext:
+		name, value = "COMMENT", unquote(c.STRING_LITERAL().GetText())
+		l.CurrentTable.Comment = value
+	default:
+		if i := strings.Index(text, "="); i >= 0 {
+			name, value = text[:i], text[i+1:]
+		} else if i := strings.LastIndexAny(text, " \t\r\n"); i >= 0 {
+			name, value = text[:i], text[i+1:]
+		}
+		name = strings.ToUpper(strings.Join(strings.Fields(name), " "))
+		name = strings.TrimPrefix(name, "DEFAULT ")
+		if name == "CHARACTER SET" {
+			name = "CHARSET"
+		}
+		value = unquote(strings.TrimSpace(value))
+	}
+	l.CurrentTable.SetOption(name, value)
+}
+
+func (l *Listener) EnterColumnDeclaration(ctx *gen.ColumnDeclarationContext) {
+	if l.CurrentCol != nil {
+		l.Error(ctx, "column %s is not done", l.CurrentCol.Name)
+	}
+	l.CurrentCol = &schema.Column{
+		Name:     uid(ctx.Uid()),
+		Nullable: true,
+	}
+}
+func (l *Listener) ExitColumnDeclaration(ctx *gen.ColumnDeclarationContext) {
+	if l.CurrentTable == nil || l.CurrentCol == nil {
+		l.Error(ctx, "column done but no table")
+		l.CurrentCol = nil
+		return
+	}
+	l.CurrentTable.Columns = append(l.CurrentTable.Columns, l.CurrentCol)
+	l.addColumnIndex(l.CurrentCol)
+	if pk := l.CurrentTable.PrimaryKey(); pk != nil && l.CurrentCol.PrimaryKey {
+		if clustered := l.clustered(ctx); clustered != "" {
+			pk.Clustered = clustered
+		}
+	}
+	l.CurrentCol = nil
+}
+
+// addColumnIndex adds the indexes declared by column constraints,
+// e.g. `id bigint PRIMARY KEY`.
+func (l *Listener) addColumnIndex(col *schema.Column) {
+	if col.PrimaryKey && l.CurrentTable.PrimaryKey() == nil {
+		l.addIndex(&schema.Index{
+			Kind:    schema.IndexPrimary,
+			Columns: []*schema.IndexColumn{{Name: col.Name}},
+		})
+	}
+	if col.Unique {
+		l.addIndex(&schema.Index{
+			Kind:    schema.IndexUnique,
+			Columns: []*schema.IndexColumn{{Name: col.Name}},
+		})
+	}
+}
+
+/////////////////////////////////////////////
+// string ///////////////////////////////////
+/////////////////////////////////////////////
+func (l *Listener) EnterStringDataType(c *gen.StringDataTypeContext) {
+	l.SetDataType(c, c.GetTypeName().GetText())
+	if l.CurrentCol == nil {
+		return
+	}
+	if c.CharsetName() != nil {
+		l.CurrentCol.Type.Charset = unquote(c.CharsetName().GetText())
+	}
+	if c.CollationName() != nil {
+		l.CurrentCol.Collation = unquote(c.CollationName().GetText())
+	}
+}
+func (l *Listener) EnterNationalStringDataType(c *gen.NationalStringDataTypeContext) {
+	l.SetDataType(c, c.GetTypeName().GetText())
+}
+func (l *Listener) EnterNationalVaryingStringDataType(c *gen.NationalVaryingStringDataTypeContext) {
+	l.SetDataType(c, "varchar")
+}
+
+/////////////////////////////////////////////
+// Dimension ////////////////////////////////
+/////////////////////////////////////////////
+
+func (l *Listener) EnterDimensionDataType(c *gen.DimensionDataTypeContext) {
+	l.SetDataType(c, c.GetTypeName().GetText())
+	if l.CurrentCol == nil {
+		return
+	}
+	l.CurrentCol.Type.Unsigned = c.UNSIGNED() != nil
+	l.CurrentCol.Type.Zerofill = c.ZEROFILL() != nil
+}
+
+func (l *Listener) EnterLengthOneDimension(c *gen.LengthOneDimensionContext) {
+	l.setLength(c, c.DecimalLiteral(), nil)
+}
+func (l *Listener) EnterLengthTwoDimension(c *gen.LengthTwoDimensionContext) {
+	l.setLength(c, c.DecimalLiteral(0), c.DecimalLiteral(1))
+}
+func (l *Listener) EnterLengthTwoOptionalDimension(c *gen.LengthTwoOptionalDimensionContext) {
+	l.setLength(c, c.DecimalLiteral(0), c.DecimalLiteral(1))
+}
+
+func (l *Listener) setLength(c antlr.ParserRuleContext, length, scale gen.IDecimalLiteralContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	if _, ok := c.GetParent().(gen.IDataTypeContext); !ok {
+		return
+	}
+	if length != nil {
+		l.CurrentCol.Type.Length = atoi(length.GetText())
+	}
+	if scale != nil {
+		l.CurrentCol.Type.Scale = atoi(scale.GetText())
+	}
+}
+
+/////////////////////////////////////////////
+// simple data type//////////////////////////
+/////////////////////////////////////////////
+
+func (l *Listener) EnterSimpleDataType(c *gen.SimpleDataTypeContext) {
+	l.SetDataType(c, c.GetTypeName().GetText())
+}
+
+/////////////////////////////////////////////
+// collection data type//////////////////////
+/////////////////////////////////////////////
+
+func (l *Listener) EnterCollectionDataType(c *gen.CollectionDataTypeContext) {
+	l.SetDataType(c, c.GetTypeName().GetText())
+	if l.CurrentCol == nil {
+		return
+	}
+	for _, value := range c.CollectionOptions().(*gen.CollectionOptionsContext).AllSTRING_LITERAL() {
+		l.CurrentCol.Type.Values = append(l.CurrentCol.Type.Values, unquote(value.GetText()))
+	}
+	if c.CharsetName() != nil {
+		l.CurrentCol.Type.Charset = unquote(c.CharsetName().GetText())
+	}
+}
+
+/////////////////////////////////////////////
+// spatial data type/////////////////////////
+/////////////////////////////////////////////
+func (l *Listener) EnterSpatialDataType(c *gen.SpatialDataTypeContext) {
+	l.SetDataType(c, c.GetTypeName().GetText())
+}
+
+/////////////////////////////////////////////
+// long varchar data type////////////////////
+/////////////////////////////////////////////
+
+// LONG and LONG VARCHAR are the same as MEDIUMTEXT.
+func (l *Listener) EnterLongVarcharDataType(c *gen.LongVarcharDataTypeContext) {
+	l.SetDataType(c, "mediumtext")
+	if l.CurrentCol == nil {
+		return
+	}
+	if c.CharsetName() != nil {
+		l.CurrentCol.Type.Charset = unquote(c.CharsetName().GetText())
+	}
+	if c.CollationName() != nil {
+		l.CurrentCol.Collation = unquote(c.CollationName().GetText())
+	}
+}
+
+/////////////////////////////////////////////
+// long varbinary data type//////////////////
+/////////////////////////////////////////////
+
+// LONG VARBINARY is the same as MEDIUMBLOB.
+func (l *Listener) EnterLongVarbinaryDataType(c *gen.LongVarbinaryDataTypeContext) {
+	l.SetDataType(c, "mediumblob")
+}
+
+/////////////////////////////////////////////
+// null /////////////////////////////////////
+/////////////////////////////////////////////
+func (l *Listener) EnterNullColumnConstraint(c *gen.NullColumnConstraintContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	l.CurrentCol.Nullable = c.NullNotnull().(*gen.NullNotnullContext).NOT() == nil
+}
+
+/////////////////////////////////////////////
+// comment //////////////////////////////////
+/////////////////////////////////////////////
+func (l *Listener) EnterCommentColumnConstraint(c *gen.CommentColumnConstraintContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	l.CurrentCol.Comment = unquote(c.STRING_LITERAL().GetText())
+}
+
+/////////////////////////////////////////////
+// default //////////////////////////////////
+/////////////////////////////////////////////
+func (l *Listener) EnterDefaultColumnConstraint(c *gen.DefaultColumnConstraintContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	dv := c.DefaultValue().(*gen.DefaultValueContext)
+	value := originalText(dv)
+	if on := dv.ON(); on != nil {
+		// DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+		start := dv.GetStart()
+		value = strings.TrimSpace(start.GetInputStream().GetText(start.GetStart(), on.GetSymbol().GetStart()-1))
+		timestamps := dv.AllCurrentTimestamp()
+		l.CurrentCol.OnUpdate = originalText(timestamps[len(timestamps)-1])
+	}
+	if len(l.rewrites) > 0 {
+		value = l.sequenceDefault(dv, value)
+	}
+	l.CurrentCol.Default = &value
+}
+
+/////////////////////////////////////////////
+// auto increment and on update /////////////
+/////////////////////////////////////////////
+func (l *Listener) EnterAutoIncrementColumnConstraint(c *gen.AutoIncrementColumnConstraintContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	if c.AUTO_INCREMENT() != nil {
+		l.CurrentCol.AutoIncrement = true
+		return
+	}
+	l.CurrentCol.OnUpdate = originalText(c.CurrentTimestamp())
+}
+
+// SERIAL DEFAULT VALUE is NOT NULL AUTO_INCREMENT UNIQUE.
+func (l *Listener) EnterSerialDefaultColumnConstraint(c *gen.SerialDefaultColumnConstraintContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	l.CurrentCol.AutoIncrement = true
+	l.CurrentCol.Nullable = false
+	l.CurrentCol.Unique = true
+}
+
+/////////////////////////////////////////////
+// key //////////////////////////////////////
+/////////////////////////////////////////////
+func (l *Listener) EnterPrimaryKeyColumnConstraint(c *gen.PrimaryKeyColumnConstraintContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	l.CurrentCol.PrimaryKey = true
+	l.CurrentCol.Nullable = false
+}
+func (l *Listener) EnterUniqueKeyColumnConstraint(c *gen.UniqueKeyColumnConstraintContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	l.CurrentCol.Unique = true
+}
+
+/////////////////////////////////////////////
+// others ///////////////////////////////////
+/////////////////////////////////////////////
+func (l *Listener) EnterCollateColumnConstraint(c *gen.CollateColumnConstraintContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	l.CurrentCol.Collation = unquote(c.CollationName().GetText())
+}
+func (l *Listener) EnterGeneratedColumnConstraint(c *gen.GeneratedColumnConstraintContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	l.CurrentCol.Generated = originalText(c.Expression())
+	l.CurrentCol.Stored = c.STORED() != nil
+}
+func (l *Listener) EnterReferenceColumnConstraint(c *gen.ReferenceColumnConstraintContext) {
+	if l.CurrentTable == nil || l.CurrentCol == nil {
+		return
+	}
+	fk := &schema.Constraint{
+		Type:    schema.ConstraintForeignKey,
+		Columns: []string{l.CurrentCol.Name},
+	}
+	l.setReference(fk, c.ReferenceDefinition())
+	l.CurrentTable.Constraints = append(l.CurrentTable.Constraints, fk)
+}
+func (l *Listener) EnterCheckColumnConstraint(c *gen.CheckColumnConstraintContext) {
+	if l.CurrentTable == nil || l.CurrentCol == nil {
+		return
+	}
+	l.CurrentTable.Constraints = append(l.CurrentTable.Constraints, &schema.Constraint{
+		Name:        uid(c.GetName()),
+		Type:        schema.ConstraintCheck,
+		Columns:     []string{l.CurrentCol.Name},
+		Check:       originalText(c.Expression()),
+		NotEnforced: l.notEnforced(c),
+	})
+}
+
+/////////////////////////////////////////////
+// table constraint and index ///////////////
+/////////////////////////////////////////////
+func (l *Listener) EnterPrimaryKeyTableConstraint(c *gen.PrimaryKeyTableConstraintContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	idx := l.newIndex(schema.IndexPrimary, nil, c.IndexType(), c.IndexColumnNames(), c.AllIndexOption())
+	idx.Clustered = l.clustered(c)
+	l.addIndex(idx)
+	for _, name := range idx.ColumnNames() {
+		if col := l.CurrentTable.Column(name); col != nil {
+			col.PrimaryKey = true
+			col.Nullable = false
+		}
+	}
+}
+func (l *Listener) EnterUniqueKeyTableConstraint(c *gen.UniqueKeyTableConstraintContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	name := c.GetIndex()
+	if name == nil {
+		name = c.GetName()
+	}
+	l.addIndex(l.newIndex(schema.IndexUnique, name, c.IndexType(), c.IndexColumnNames(), c.AllIndexOption()))
+}
+func (l *Listener) EnterForeignKeyTableConstraint(c *gen.ForeignKeyTableConstraintContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	name := c.GetName()
+	if name == nil {
+		name = c.GetIndex()
+	}
+	fk := &schema.Constraint{
+		Name:    uid(name),
+		Type:    schema.ConstraintForeignKey,
+		Columns: l.newIndex("", nil, nil, c.IndexColumnNames(), nil).ColumnNames(),
+	}
+	l.setReference(fk, c.ReferenceDefinition())
+	l.CurrentTable.Constraints = append(l.CurrentTable.Constraints, fk)
+}
+func (l *Listener) EnterCheckTableConstraint(c *gen.CheckTableConstraintContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	l.CurrentTable.Constraints = append(l.CurrentTable.Constraints, &schema.Constraint{
+		Name:        uid(c.GetName()),
+		Type:        schema.ConstraintCheck,
+		Check:       originalText(c.Expression()),
+		NotEnforced: l.notEnforced(c),
+	})
+}
+func (l *Listener) EnterSimpleIndexDeclaration(c *gen.SimpleIndexDeclarationContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	l.addIndex(l.newIndex(schema.IndexNormal, c.Uid(), c.IndexType(), c.IndexColumnNames(), c.AllIndexOption()))
+}
+func (l *Listener) EnterSpecialIndexDeclaration(c *gen.SpecialIndexDeclarationContext) {
+	if l.CurrentTable == nil {
+		return
+	}
+	kind := schema.IndexFulltext
+	if c.SPATIAL() != nil {
+		kind = schema.IndexSpatial
+	}
+	l.addIndex(l.newIndex(kind, c.Uid(), nil, c.IndexColumnNames(), c.AllIndexOption()))
+}
+
+// addIndex adds idx to the current table,
+// an unnamed index is named after its first column like mysql does,
+// or functional_index if it is a functional key part.
+func (l *Listener) addIndex(idx *schema.Index) {
+	if idx.Name == "" && idx.Kind != schema.IndexPrimary && len(idx.Columns) > 0 {
+		name := idx.Columns[0].Name
+		if name == "" {
+			name = "functional_index"
+		}
+		idx.Name = name
+		for i := 2; l.CurrentTable.Index(idx.Name) != nil; i++ {
+			idx.Name = fmt.Sprintf("%s_%d", name, i)
+		}
+	}
+	l.CurrentTable.Indexes = append(l.CurrentTable.Indexes, idx)
+}
+
+// newIndex returns the index of columns, a functional key part has an expression instead of a name.
+func (l *Listener) newIndex(kind string, name gen.IUidContext, indexType gen.IIndexTypeContext,
+	columns gen.IIndexColumnNamesContext, options []gen.IIndexOptionContext) *schema.Index {
+	idx := &schema.Index{
+		Name: uid(name),
+		Kind: kind,
+	}
+	if indexType != nil {
+		idx.Using = strings.ToUpper(indexType.GetChild(1).(antlr.TerminalNode).GetText())
+	}
+	for _, option := range options {
+		o := option.(*gen.IndexOptionContext)
+		switch {
+		case o.IndexType() != nil:
+			idx.Using = strings.ToUpper(o.IndexType().GetChild(1).(antlr.TerminalNode).GetText())
+		case o.COMMENT() != nil:
+			idx.Comment = unquote(o.STRING_LITERAL().GetText())
+		}
+	}
+	if columns == nil {
+		return idx
+	}
+	for _, column := range columns.(*gen.IndexColumnNamesContext).AllIndexColumnName() {
+		c := column.(*gen.IndexColumnNameContext)
+		col := &schema.IndexColumn{
+			Desc: c.DESC() != nil,
+		}
+		expression, functional := l.expression(c.Uid())
+		switch {
+		case functional:
+			col.Expression = expression
+		case c.Uid() != nil:
+			col.Name = uid(c.Uid())
+		default:
+			col.Name = unquote(c.STRING_LITERAL().GetText())
+		}
+		if c.DecimalLiteral() != nil {
+			col.Length = atoi(c.DecimalLiteral().GetText())
+		}
+		idx.Columns = append(idx.Columns, col)
+	}
+	return idx
+}
+
+func (l *Listener) setReference(fk *schema.Constraint, ctx gen.IReferenceDefinitionContext) {
+	ref := ctx.(*gen.ReferenceDefinitionContext)
+	_, fk.RefTable = tableName(ref.TableName())
+	if ref.IndexColumnNames() != nil {
+		fk.RefColumns = l.newIndex("", nil, nil, ref.IndexColumnNames(), nil).ColumnNames()
+	}
+	if ref.ReferenceAction() == nil {
+		return
+	}
+	action := ref.ReferenceAction().(*gen.ReferenceActionContext)
+	if action.GetOnDelete() != nil {
+		fk.OnDelete = strings.ToUpper(originalText(action.GetOnDelete()))
+	}
+	if action.GetOnUpdate() != nil {
+		fk.OnUpdate = strings.ToUpper(originalText(action.GetOnUpdate()))
+	}
+}
+
+/////////////////////////////////////////////
+/////////////////////////////////////////////
+/////////////////////////////////////////////
+
+// SetDataType sets the type of the current column, typeName is the sql type name.
+func (l *Listener) SetDataType(ctx antlr.ParserRuleContext, typeName string) {
+	if l.CurrentCol == nil {
+		if _, ok := ctx.GetParent().(*gen.ColumnDefinitionContext); ok && l.CurrentTable != nil {
+			l.option.warnf("get data type but no col: %s", typeName)
+		}
+		return
+	}
+	l.CurrentCol.Type = schema.DataType{
+		Name: strings.ToLower(typeName),
+		Raw:  originalText(ctx),
+	}
+}
+
+// originalText returns the text of ctx as written in the input, empty if ctx is nil,
+// the lexer only sees the upper case text.
+func originalText(ctx antlr.ParserRuleContext) string {
+	if ctx == nil {
+		return ""
+	}
+	start, stop := ctx.GetStart(), ctx.GetStop()
+	if start == nil || stop == nil || stop.GetStop() < start.GetStart() {
+		return ctx.GetText()
+	}
+	return start.GetInputStream().GetText(start.GetStart(), stop.GetStop())
+}
+
+// uid returns the unquoted identifier, empty if ctx is nil.
+func uid(ctx gen.IUidContext) string {
+	if ctx == nil {
+		return ""
+	}
+	return unquoteID(originalText(ctx))
+}
+
+func unquoteID(s string) string {
+	if len(s) >= 2 && s[0] == '`' && s[len(s)-1] == '`' {
+		return strings.ReplaceAll(s[1:len(s)-1], "``", "`")
+	}
+	return s
+}
+
+func (l *Listener) EnterUseStatement(ctx *gen.UseStatementContext) {
+	l.Database = uid(ctx.Uid())
+}
+
+// tableName splits a possibly schema-qualified table name,
+// the database is the current database if not qualified.
+func (l *Listener) tableName(ctx antlr.ParserRuleContext) (database string, name string) {
+	database, name = tableName(ctx)
+	if database == "" {
+		database = l.Database
+	}
+	return database, name
+}
+
+// tableName splits a possibly schema-qualified table name.
+func tableName(ctx antlr.ParserRuleContext) (database string, name string) {
+	parts := splitName(originalText(ctx))
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], "."), parts[len(parts)-1]
+}
+
+// splitName splits a dotted name like `db`.`t` into unquoted parts.
+func splitName(text string) []string {
+	parts := make([]string, 0, 2)
+	quoted, start := false, 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '`':
+			quoted = !quoted
+		case '.':
+			if !quoted {
+				parts = append(parts, unquoteID(strings.TrimSpace(text[start:i])))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, unquoteID(strings.TrimSpace(text[start:])))
+}
+
+// unquote returns the value of a sql string literal,
+// s is returned as is if it is not quoted.
+func unquote(s string) string {
+	if len(s) < 2 {
+		return s
+	}
+	q := s[0]
+	if (q != '\'' && q != '"') || s[len(s)-1] != q {
+		return unquoteID(s)
+	}
+	s = s[1 : len(s)-1]
+	buf := new(strings.Builder)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == q && i+1 < len(s) && s[i+1] == q:
+			buf.WriteByte(q)
+			i++
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case '0':
+				buf.WriteByte(0)
+			case 'Z':
+				buf.WriteByte(26)
+			default:
+				buf.WriteByte(s[i])
+			}
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	return buf.String()
+}
+
+func atoi(s string) *int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
@@ -0,0 +1,350 @@
+package convert
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	gen "github.com/er1c-zh/sql-to-gorm/antlr4_gen"
+)
+
+// The grammar is that of MySQL 5.7. MySQL 8.0 constructs it does not know are rewritten
+// where the parser fails on them into text of the same length it accepts, and the
+// listener reads them back from the rewrites of the statement, see parser.parseStatement.
+const (
+	// INVISIBLE and VISIBLE column attributes are blanked.
+	rewriteInvisible = "INVISIBLE"
+	rewriteVisible   = "VISIBLE"
+	// SRID n column attributes are blanked, value is n.
+	rewriteSRID = "SRID"
+	// [NOT] ENFORCED after a check constraint is blanked.
+	rewriteEnforced    = "ENFORCED"
+	rewriteNotEnforced = "NOT ENFORCED"
+	// a functional key part becomes a quoted name, e.g. (lower(email)) is parsed
+	// as `___________`, value is the expression.
+	rewriteExpression = "EXPRESSION"
+	// ALTER [COLUMN] c SET [IN]VISIBLE of an ALTER TABLE is blanked, value is the column.
+	rewriteSetVisible   = "SET VISIBLE"
+	rewriteSetInvisible = "SET INVISIBLE"
+	// ALTER CHECK|CONSTRAINT c [NOT] ENFORCED of an ALTER TABLE is blanked, value is the constraint.
+	rewriteAlterEnforced    = "ALTER ENFORCED"
+	rewriteAlterNotEnforced = "ALTER NOT ENFORCED"
+)
+
+// rewrite is a construct rewritten in a statement.
+type rewrite struct {
+	kind string
+	// offset is the offset in runes of the construct in the statement,
+	// the start index of its tokens.
+	offset int
+	value  string
+}
+
+// maxRewrites bounds the times a statement is parsed again, each rewrite parses it
+// once more. Statements seldom have more than a few such constructs, one needing
+// more is reported with the errors of the statement as written.
+const maxRewrites = 16
+
+// rewrite8 rewrites the MySQL 8.0 construct at offset of statement, in place,
+// false if there is none the parser may have failed on.
+func rewrite8(statement []rune, offset int) (rewrite, bool) {
+	if offset < 0 || offset >= len(statement) {
+		return rewrite{}, false
+	}
+	if statement[offset] == '(' {
+		// a key part starts a list of key parts or follows one
+		before := offset - 1
+		for before >= 0 && unicode.IsSpace(statement[before]) {
+			before--
+		}
+		end := closingParen(statement, offset)
+		if before < 0 || statement[before] != '(' && statement[before] != ',' || end < 0 {
+			return rewrite{}, false
+		}
+		expression := strings.TrimSpace(string(statement[offset+1 : end]))
+		if expression == "" {
+			return rewrite{}, false
+		}
+		statement[offset], statement[end] = '`', '`'
+		for i := offset + 1; i < end; i++ {
+			if statement[i] != '\n' {
+				statement[i] = '_'
+			}
+		}
+		return rewrite{kind: rewriteExpression, offset: offset, value: expression}, true
+	}
+
+	word, end := wordAt(statement, offset)
+	switch word {
+	case rewriteEnforced:
+		// NOT is taken for the start of NOT NULL, the parser fails after it
+		if before, start := wordBefore(statement, offset); before == "NOT" {
+			blankRunes(statement[start:end])
+			return rewrite{kind: rewriteNotEnforced, offset: start}, true
+		}
+		blankRunes(statement[offset:end])
+		return rewrite{kind: word, offset: offset}, true
+	case rewriteInvisible, rewriteVisible:
+		if r, ok := rewriteAlterVisibility(statement, offset, end, word); ok {
+			return r, true
+		}
+		blankRunes(statement[offset:end])
+		return rewrite{kind: word, offset: offset}, true
+	case "CHECK", "CONSTRAINT":
+		return rewriteAlterEnforcement(statement, offset, end)
+	case "NOT":
+		if next, nextEnd := wordAt(statement, skipSpaces(statement, end)); next == rewriteEnforced {
+			blankRunes(statement[offset:nextEnd])
+			return rewrite{kind: rewriteNotEnforced, offset: offset}, true
+		}
+	case rewriteSRID:
+		start := skipSpaces(statement, end)
+		numberEnd := start
+		for numberEnd < len(statement) && '0' <= statement[numberEnd] && statement[numberEnd] <= '9' {
+			numberEnd++
+		}
+		if numberEnd == start {
+			return rewrite{}, false
+		}
+		value := string(statement[start:numberEnd])
+		blankRunes(statement[offset:numberEnd])
+		return rewrite{kind: rewriteSRID, offset: offset, value: value}, true
+	}
+	return rewrite{}, false
+}
+
+// rewriteAlterVisibility blanks the specification ALTER [COLUMN] c SET [IN]VISIBLE
+// of an ALTER TABLE, word at offset is its last one.
+func rewriteAlterVisibility(statement []rune, offset, end int, word string) (rewrite, bool) {
+	set, setStart := wordBefore(statement, offset)
+	if set != "SET" {
+		return rewrite{}, false
+	}
+	name, nameStart := nameBefore(statement, setStart)
+	if name == "" {
+		return rewrite{}, false
+	}
+	before, start := wordBefore(statement, nameStart)
+	if before == "COLUMN" {
+		before, start = wordBefore(statement, start)
+	}
+	if before != "ALTER" {
+		return rewrite{}, false
+	}
+	blankSpecification(statement, start, end)
+	return rewrite{kind: "SET " + word, offset: start, value: name}, true
+}
+
+// rewriteAlterEnforcement blanks the specification ALTER CHECK|CONSTRAINT c [NOT] ENFORCED
+// of an ALTER TABLE, CHECK or CONSTRAINT is at offset.
+func rewriteAlterEnforcement(statement []rune, offset, end int) (rewrite, bool) {
+	alter, start := wordBefore(statement, offset)
+	if alter != "ALTER" {
+		return rewrite{}, false
+	}
+	name, nameEnd := nameAt(statement, skipSpaces(statement, end))
+	if name == "" {
+		return rewrite{}, false
+	}
+	kind := rewriteAlterEnforced
+	word, wordEnd := wordAt(statement, skipSpaces(statement, nameEnd))
+	if word == "NOT" {
+		kind = rewriteAlterNotEnforced
+		word, wordEnd = wordAt(statement, skipSpaces(statement, wordEnd))
+	}
+	if word != rewriteEnforced {
+		return rewrite{}, false
+	}
+	blankSpecification(statement, start, wordEnd)
+	return rewrite{kind: kind, offset: start, value: name}, true
+}
+
+// blankSpecification blanks statement[start:end], a specification of an ALTER TABLE,
+// and the comma separating it from the one before or after it.
+func blankSpecification(statement []rune, start, end int) {
+	if before := skipSpacesBack(statement, start); before > 0 && statement[before-1] == ',' {
+		start = before - 1
+	} else if after := skipSpaces(statement, end); after < len(statement) && statement[after] == ',' {
+		end = after + 1
+	}
+	blankRunes(statement[start:end])
+}
+
+// nameAt returns the name, quoted or not, starting at offset and its end, empty if there is none.
+func nameAt(s []rune, offset int) (string, int) {
+	if offset < len(s) && s[offset] == '`' {
+		end := closingQuote(s, offset)
+		if end >= len(s) {
+			return "", offset
+		}
+		return strings.ReplaceAll(string(s[offset+1:end]), "``", "`"), end + 1
+	}
+	end := offset
+	for end < len(s) && isNameRune(s[end]) {
+		end++
+	}
+	return string(s[offset:end]), end
+}
+
+// nameBefore returns the name, quoted or not, before offset with only spaces between and its start,
+// empty if there is none.
+func nameBefore(s []rune, offset int) (string, int) {
+	end := skipSpacesBack(s, offset)
+	if end > 0 && s[end-1] == '`' {
+		start := end - 2
+		for start >= 0 && s[start] != '`' {
+			start--
+		}
+		if start < 0 {
+			return "", offset
+		}
+		return string(s[start+1 : end-1]), start
+	}
+	start := end
+	for start > 0 && isNameRune(s[start-1]) {
+		start--
+	}
+	return string(s[start:end]), start
+}
+
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$'
+}
+
+// wordAt returns the upper case word at offset and its end.
+func wordAt(s []rune, offset int) (string, int) {
+	end := offset
+	for end < len(s) && (unicode.IsLetter(s[end]) || s[end] == '_') {
+		end++
+	}
+	return strings.ToUpper(string(s[offset:end])), end
+}
+
+// wordBefore returns the upper case word before offset with only spaces between and its start,
+// -1 if there is none.
+func wordBefore(s []rune, offset int) (string, int) {
+	end := offset
+	for end > 0 && unicode.IsSpace(s[end-1]) {
+		end--
+	}
+	start := end
+	for start > 0 && (unicode.IsLetter(s[start-1]) || s[start-1] == '_') {
+		start--
+	}
+	if start == end {
+		return "", -1
+	}
+	return strings.ToUpper(string(s[start:end])), start
+}
+
+func skipSpaces(s []rune, i int) int {
+	for i < len(s) && unicode.IsSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+// closingParen returns the offset of the parenthesis closing the one at open, -1 if none.
+func closingParen(s []rune, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '\'', '"', '`':
+			i = closingQuote(s, i)
+		}
+	}
+	return -1
+}
+
+// closingQuote returns the offset of the quote closing the one at open, len(s) if none.
+func closingQuote(s []rune, open int) int {
+	i := open + 1
+	for ; i < len(s) && s[i] != s[open]; i++ {
+		if s[i] == '\\' && s[open] != '`' {
+			i++
+		}
+	}
+	return i
+}
+
+// blankRunes replaces s by spaces, keeping line breaks.
+func blankRunes(s []rune) {
+	for i, r := range s {
+		if r != '\n' && r != '\r' {
+			s[i] = ' '
+		}
+	}
+}
+
+// rewritten returns the rewrites of kind in ctx or right after it with only spaces between,
+// e.g. INVISIBLE after the type of a column definition.
+func (l *Listener) rewritten(ctx antlr.ParserRuleContext, kind string) []rewrite {
+	if len(l.rewrites) == 0 || ctx == nil {
+		return nil
+	}
+	start, stop := ctx.GetStart(), ctx.GetStop()
+	if start == nil || stop == nil {
+		return nil
+	}
+	var result []rewrite
+	for _, r := range l.rewrites {
+		if r.kind != kind || r.offset < start.GetStart() {
+			continue
+		}
+		if r.offset > stop.GetStop() &&
+			strings.TrimSpace(start.GetInputStream().GetText(stop.GetStop()+1, r.offset-1)) != "" {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// setColumnAttributes sets the attributes of the current column rewritten in its definition.
+func (l *Listener) setColumnAttributes(ctx *gen.ColumnDefinitionContext) {
+	if l.CurrentCol == nil {
+		return
+	}
+	for _, r := range l.rewritten(ctx, rewriteSRID) {
+		if _, err := strconv.Atoi(r.value); err == nil {
+			l.CurrentCol.SetOption(rewriteSRID, r.value)
+		}
+	}
+	// the last of INVISIBLE and VISIBLE wins like in mysql
+	invisible, visible := l.rewritten(ctx, rewriteInvisible), l.rewritten(ctx, rewriteVisible)
+	switch {
+	case len(invisible) == 0:
+		l.CurrentCol.Invisible = false
+	case len(visible) == 0:
+		l.CurrentCol.Invisible = true
+	default:
+		l.CurrentCol.Invisible = invisible[len(invisible)-1].offset > visible[len(visible)-1].offset
+	}
+	l.setDialectColumnAttributes(ctx)
+}
+
+// expression returns the expression of the functional key part ctx rewritten as a name.
+func (l *Listener) expression(ctx antlr.ParserRuleContext) (string, bool) {
+	if ctx == nil || ctx.GetStart() == nil {
+		return "", false
+	}
+	for _, r := range l.rewrites {
+		if r.kind == rewriteExpression && r.offset == ctx.GetStart().GetStart() {
+			return r.value, true
+		}
+	}
+	return "", false
+}
+
+// notEnforced reports whether the check constraint ctx is followed by NOT ENFORCED.
+func (l *Listener) notEnforced(ctx antlr.ParserRuleContext) bool {
+	return len(l.rewritten(ctx, rewriteNotEnforced)) > 0
+}
@@ -0,0 +1,67 @@
+package convert
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// GoName returns an exported go identifier for a sql name, like SnakeToCamel:
+// user_info is UserInfo, characters which are not letters or digits separate words,
+// e.g. `some table $$` is SomeTable, and X is prepended if the name does not start
+// with an upper case letter, e.g. X1st for 1st.
+func GoName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	buf := new(strings.Builder)
+	for _, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		buf.WriteString(string(runes))
+	}
+	result := buf.String()
+	for _, r := range result {
+		if !unicode.IsUpper(r) {
+			result = "X" + result
+		}
+		break
+	}
+	if result == "" {
+		return "X"
+	}
+	return result
+}
+
+// StructNames returns the names of the models of the tables of s,
+// a name taken by a table before gets a number, e.g. UserInfo2.
+func StructNames(s *schema.Schema) map[*schema.Table]string {
+	result := make(map[*schema.Table]string, len(s.Tables))
+	taken := map[string]bool{}
+	for _, t := range s.Tables {
+		result[t] = unique(GoName(t.Name), taken)
+	}
+	return result
+}
+
+// FieldNames returns the names of the fields of the columns of t,
+// a name taken by a column before gets a number, e.g. UserId2 for user_id and UserId.
+func FieldNames(t *schema.Table) map[*schema.Column]string {
+	result := make(map[*schema.Column]string, len(t.Columns))
+	taken := map[string]bool{}
+	for _, c := range t.Columns {
+		result[c] = unique(GoName(c.Name), taken)
+	}
+	return result
+}
+
+func unique(name string, taken map[string]bool) string {
+	result := name
+	for i := 2; taken[result]; i++ {
+		result = name + strconv.Itoa(i)
+	}
+	taken[result] = true
+	return result
+}
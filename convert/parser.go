@@ -0,0 +1,197 @@
+package convert
+
+import (
+	"fmt"
+	"sync"
+
+	res "github.com/antlr/antlr4/doc/resources"
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	gen "github.com/er1c-zh/sql-to-gorm/antlr4_gen"
+)
+
+// parser is a lexer and a parser reused for many inputs: creating them deserializes
+// the ATN, and the DFA they build while predicting makes the next inputs faster.
+type parser struct {
+	lexer  *gen.MySqlLexer
+	parser *gen.MySqlParser
+}
+
+func newParser() *parser {
+	lexer := gen.NewMySqlLexer(nil)
+	lexer.RemoveErrorListeners()
+	p := gen.NewMySqlParser(nil)
+	p.RemoveErrorListeners()
+	p.BuildParseTrees = true
+	return &parser{
+		lexer:  lexer,
+		parser: p,
+	}
+}
+
+// parse returns the tree of a statement starting at pos of its script,
+// errors are reported to errorListener at their position in the script.
+//
+// The statement is parsed with SLL prediction first, which is much faster but may fail
+// on valid input, bailing out at the first error. Only if it fails the statement
+// is parsed again with full LL prediction, which reports the errors.
+func (p *parser) parse(statement string, pos position, errorListener antlr.ErrorListener) gen.IRootContext {
+	p.lexer.SetInputStream(res.NewCaseChangingStream(antlr.NewInputStream(statement), true))
+	simulator := p.lexer.Interpreter.(*antlr.LexerATNSimulator)
+	simulator.Line = pos.line
+	simulator.CharPositionInLine = pos.column
+	p.lexer.RemoveErrorListeners()
+	p.lexer.AddErrorListener(errorListener)
+	// the tokens are lexed once, lexer errors are not reported again by the second stage.
+	tokens := antlr.NewCommonTokenStream(p.lexer, antlr.TokenDefaultChannel)
+
+	if tree := p.parseSLL(tokens); tree != nil {
+		return tree
+	}
+
+	// SetTokenStream does not rewind the stream it is given.
+	tokens.Seek(0)
+	p.parser.SetTokenStream(tokens)
+	p.parser.SetErrorHandler(antlr.NewDefaultErrorStrategy())
+	p.parser.Interpreter.SetPredictionMode(antlr.PredictionModeLL)
+	p.parser.AddErrorListener(errorListener)
+	defer p.parser.RemoveErrorListeners()
+	return p.parser.Root()
+}
+
+// parseSLL returns the tree of tokens parsed with SLL prediction, nil on the first error.
+func (p *parser) parseSLL(tokens *antlr.CommonTokenStream) (tree gen.IRootContext) {
+	p.parser.SetTokenStream(tokens)
+	p.parser.SetErrorHandler(antlr.NewBailErrorStrategy())
+	p.parser.Interpreter.SetPredictionMode(antlr.PredictionModeSLL)
+	defer func() {
+		// the bail strategy panics to cancel the parse.
+		if r := recover(); r != nil {
+			tree = nil
+		}
+	}()
+	return p.parser.Root()
+}
+
+// parsed is the result of parsing a statement.
+type parsed struct {
+	tree gen.IRootContext
+	// pos is the start of the statement in the script
+	pos    position
+	errors []*SyntaxError
+	// rewrites are the MySQL 8.0 constructs rewritten for the grammar, see rewrite8
+	rewrites []rewrite
+}
+
+// parseAll parses the statements of script in dialect concurrently, one at a time by each parser,
+// and returns the results in the order of statements.
+func parseAll(parsers []*parser, script string, statements []Statement, dialect string) []parsed {
+	result := make([]parsed, len(statements))
+	pos := position{line: 1}
+	for i, s := range statements {
+		pos.advance(script, s.Offset)
+		result[i].pos = pos
+	}
+
+	next := make(chan int)
+	wg := sync.WaitGroup{}
+	for _, p := range parsers {
+		wg.Add(1)
+		go func(p *parser) {
+			defer wg.Done()
+			for i := range next {
+				r := &result[i]
+				r.tree, r.rewrites, r.errors = p.parseStatement(statements[i].Text, r.pos, dialect)
+			}
+		}(p)
+	}
+	for i := range statements {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return result
+}
+
+// parseStatement is parse collecting the errors of the statement,
+// a panic of the parser is returned as an error too.
+//
+// The executable comments of dialect are read, see unwrapComments. While the statement fails
+// on a construct of dialect or MySQL 8.0 the grammar does not know, it is rewritten
+// and parsed again, see rewriteDialect. If it still fails the errors of the statement
+// as written are returned.
+func (p *parser) parseStatement(statement string, pos position, dialect string) (tree gen.IRootContext, rewrites []rewrite, errors []*SyntaxError) {
+	errorListener := &syntaxErrors{DefaultErrorListener: antlr.NewDefaultErrorListener()}
+	defer func() {
+		if r := recover(); r != nil {
+			// the parser may be left in any state
+			*p = *newParser()
+			tree, rewrites = nil, nil
+			errors = append(errorListener.errors, &SyntaxError{
+				Line:   pos.line,
+				Column: pos.column,
+				Msg:    fmt.Sprintf("internal error: %v", r),
+			})
+		}
+	}()
+	text := []rune(statement)
+	unwrapComments(dialect, text)
+	statement = string(text)
+	tree = p.parse(statement, pos, errorListener)
+	if len(errorListener.errors) == 0 {
+		return tree, nil, nil
+	}
+	errors = errorListener.errors
+
+	for len(rewrites) < maxRewrites {
+		r, ok := rewriteDialect(dialect, text, errorListener.offsets[0])
+		if !ok {
+			break
+		}
+		rewrites = append(rewrites, r)
+		errorListener = &syntaxErrors{DefaultErrorListener: antlr.NewDefaultErrorListener()}
+		tree = p.parse(string(text), pos, errorListener)
+		if len(errorListener.errors) == 0 {
+			return tree, rewrites, nil
+		}
+	}
+	return nil, nil, errors
+}
+
+// syntaxErrors collects the errors of a statement parsed concurrently,
+// they are added to the ErrorListener of the script in the order of statements.
+type syntaxErrors struct {
+	*antlr.DefaultErrorListener
+	errors []*SyntaxError
+	// offsets are the offsets in runes of the offending tokens in the statement,
+	// -1 for errors of the lexer.
+	offsets []int
+}
+
+func (l *syntaxErrors) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{},
+	line, column int, msg string, e antlr.RecognitionException) {
+	l.errors = append(l.errors, &SyntaxError{Line: line, Column: column, Msg: msg})
+	offset := -1
+	if token, ok := offendingSymbol.(antlr.Token); ok {
+		offset = token.GetStart()
+	}
+	l.offsets = append(l.offsets, offset)
+}
+
+// position is a byte offset of a script and its line and column,
+// counted like the lexer does: lines from 1, columns in runes from 0.
+type position struct {
+	offset, line, column int
+}
+
+// advance moves pos forward to offset of script.
+func (pos *position) advance(script string, offset int) {
+	for _, r := range script[pos.offset:offset] {
+		if r == '\n' {
+			pos.line++
+			pos.column = 0
+		} else {
+			pos.column++
+		}
+	}
+	pos.offset = offset
+}
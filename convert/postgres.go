@@ -0,0 +1,242 @@
+package convert
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// postgres reads the DDL of PostgreSQL, e.g. written by pg_dump --schema-only, see frontend.
+type postgres struct{}
+
+func (postgres) syntax() dialectSyntax {
+	return dialectSyntax{
+		quotes:       map[byte]byte{'"': '"'},
+		dollarQuotes: true,
+		metaCommands: true,
+		foldNames:    true,
+	}
+}
+
+// postgresTypes maps the names and aliases of PostgreSQL types to the MySQL types they are
+// equivalent to, other types keep their name, e.g. uuid, jsonb, bytea and timestamptz.
+var postgresTypes = map[string]string{
+	"int2":                        "smallint",
+	"integer":                     "int",
+	"int4":                        "int",
+	"int8":                        "bigint",
+	"float4":                      "float",
+	"real":                        "float",
+	"double precision":            "double",
+	"float8":                      "double",
+	"bool":                        "boolean",
+	"character varying":           "varchar",
+	"character":                   "char",
+	"bpchar":                      "char",
+	"timestamp":                   "datetime",
+	"timestamp without time zone": "datetime",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+	"bit":                         "varbit",
+	"bit varying":                 "varbit",
+}
+
+// postgresSerials maps the serial types, integers which auto increment, to their integer types.
+var postgresSerials = map[string]string{
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+	"serial":      "int",
+	"serial4":     "int",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+}
+
+func (postgres) setType(p *ddlParser, col *schema.Column, t sqlType) {
+	if t.name == "" {
+		p.errorf("mismatched input %s expecting a type", p.quote(p.peek()))
+	}
+	name := t.name
+	switch {
+	case postgresSerials[name] != "":
+		name = postgresSerials[name]
+		col.AutoIncrement = true
+		col.Nullable = false
+	case name == "float":
+		// float(p) is real up to 24 bits of precision, and double precision without p
+		if length := t.length(0); length == nil || *length > 24 {
+			name = "double"
+		}
+		t.args = nil
+	case strings.HasPrefix(name, "interval"):
+		// the fields of an interval, e.g. interval day to second
+		name = "interval"
+	case postgresTypes[name] != "":
+		name = postgresTypes[name]
+	default:
+		if userType, ok := p.l.types[name]; ok {
+			col.Type = userType
+			col.Type.Raw = t.raw
+			if t.array {
+				col.Type.Name += "[]"
+			}
+			return
+		}
+	}
+	col.Type = schema.DataType{
+		Name:   name,
+		Length: t.length(0),
+		Scale:  t.length(1),
+		Raw:    t.raw,
+	}
+	if t.array {
+		// arrays are read in their text form, e.g. {a,b}
+		col.Type.Name += "[]"
+	}
+}
+
+// setDefault sets the default of col, nextval of a sequence makes it auto increment
+// like serial does, the cast of a literal written by pg_dump is left out.
+func (postgres) setDefault(col *schema.Column, value string) {
+	if strings.HasPrefix(strings.ToLower(value), "nextval(") {
+		col.AutoIncrement = true
+		col.Default = nil
+		return
+	}
+	value = withoutCast(value)
+	col.Default = &value
+}
+
+// castType matches the type of a cast, e.g. character varying(20)[].
+var castType = regexp.MustCompile(`^[\w ."]+(\(\d+(,\s*\d+)?\))?(\[\])*$`)
+
+// withoutCast returns the literal of value without its cast, e.g. 'a' of 'a'::character varying,
+// value as it is if it is not a cast literal.
+func withoutCast(value string) string {
+	i := strings.LastIndex(value, "::")
+	if i < 0 || !castType.MatchString(value[i+2:]) {
+		return value
+	}
+	literal := strings.TrimSpace(value[:i])
+	if end, _ := quoted(literal, 0, '\'', false); strings.HasPrefix(literal, "'") && end == len(literal) {
+		return literal
+	}
+	if _, err := strconv.ParseFloat(strings.Trim(literal, "()"), 64); err == nil || strings.EqualFold(literal, "NULL") {
+		return literal
+	}
+	return value
+}
+
+func (postgres) columnConstraint(p *ddlParser, col *schema.Column) bool {
+	return false
+}
+
+// tableOptions skips the clauses after the definitions, e.g. PARTITION BY, INHERITS and WITH.
+func (postgres) tableOptions(p *ddlParser, t *schema.Table) {
+	p.skip("")
+}
+
+func (fe postgres) statement(p *ddlParser, kind string) (func(), bool) {
+	switch kind {
+	case "COMMENT":
+		return fe.comment(p), true
+	case "CREATE TYPE":
+		return fe.createType(p), true
+	case "CREATE DOMAIN":
+		return fe.createDomain(p), true
+	}
+	return nil, false
+}
+
+func (postgres) skip(p *ddlParser, kind string) bool {
+	return false
+}
+
+// comment reads COMMENT ON TABLE table IS text and COMMENT ON COLUMN table.column IS text,
+// NULL removes the comment. Comments on other objects are skipped.
+func (postgres) comment(p *ddlParser) func() {
+	start := p.peek().start
+	p.expect("COMMENT", "ON")
+	var database, table, column string
+	switch {
+	case p.accept("TABLE"):
+		database, table = p.tableName()
+	case p.accept("COLUMN"):
+		parts := p.qualifiedName()
+		if len(parts) < 2 {
+			p.pos--
+			p.errorf("column %s is not qualified by its table", parts[0])
+		}
+		column, table = parts[len(parts)-1], parts[len(parts)-2]
+		if database = strings.Join(parts[:len(parts)-2], "."); database == "" {
+			database = p.l.Database
+		}
+	default:
+		p.skip("")
+		return nil
+	}
+	p.expect("IS")
+	var text string
+	if !p.accept("NULL") {
+		if p.peek().kind != tokenString {
+			p.errorf("mismatched input %s expecting a string", p.quote(p.peek()))
+		}
+		text = p.peek().text
+		p.pos++
+	}
+	return func() {
+		t := p.l.Schema.Lookup(database, table)
+		if t == nil {
+			p.warnf(start, "comment on unknown table %s, skipped", table)
+			return
+		}
+		if column == "" {
+			t.Comment = text
+			return
+		}
+		if col := t.Column(column); col != nil {
+			col.Comment = text
+		} else {
+			p.warnf(start, "comment on unknown column %s of table %s, skipped", column, table)
+		}
+	}
+}
+
+// createType reads CREATE TYPE name AS ENUM (labels), the columns of the type are enums.
+// Other types, e.g. composite types, are skipped, their columns are strings.
+func (postgres) createType(p *ddlParser) func() {
+	p.expect("CREATE", "TYPE")
+	parts := p.qualifiedName()
+	if !p.accept("AS", "ENUM") {
+		p.skip("")
+		return nil
+	}
+	p.expect("(")
+	values := make([]string, 0)
+	for p.peek().kind == tokenString {
+		values = append(values, p.next().text)
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect(")")
+	return func() {
+		p.l.types[strings.ToLower(parts[len(parts)-1])] = schema.DataType{Name: "enum", Values: values}
+	}
+}
+
+// createDomain reads CREATE DOMAIN name [AS] type, the columns of the domain have its type.
+// Its default and checks are skipped.
+func (fe postgres) createDomain(p *ddlParser) func() {
+	p.expect("CREATE", "DOMAIN")
+	parts := p.qualifiedName()
+	p.accept("AS")
+	col := &schema.Column{}
+	fe.setType(p, col, p.sqlType())
+	p.skip("")
+	return func() {
+		p.l.types[strings.ToLower(parts[len(parts)-1])] = col.Type
+	}
+}
@@ -0,0 +1,101 @@
+package convert
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"text/template"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// GeneratedHeader marks a file as generated by sql-to-gorm.
+const GeneratedHeader = "// Code generated by sql-to-gorm. DO NOT EDIT."
+
+// Render writes the schema as a go file of gorm models,
+// using the template of opts.Template.
+func Render(s *schema.Schema, w io.Writer, opts Option) error {
+	tmpl, err := ParseTemplate(opts.Template)
+	if err != nil {
+		return err
+	}
+	return RenderTemplate(s, w, tmpl, opts)
+}
+
+// RenderTemplate writes the schema with a parsed template, see ParseTemplate.
+func RenderTemplate(s *schema.Schema, w io.Writer, tmpl *template.Template, opts Option) error {
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "models"
+	}
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	structNames := opts.StructNames
+	if structNames == nil {
+		structNames = StructNames(s)
+	}
+	fieldNames := map[*schema.Column]string{}
+	tables := map[*schema.Column]*schema.Table{}
+	for _, t := range s.Tables {
+		for c, name := range FieldNames(t) {
+			fieldNames[c] = name
+			tables[c] = t
+		}
+	}
+	tmpl.Funcs(template.FuncMap{
+		"structName": func(t *schema.Table) string {
+			return structNames[t]
+		},
+		"fieldName": func(c *schema.Column) string {
+			return fieldNames[c]
+		},
+		"gormTag": func(c *schema.Column) string {
+			return tableGormTag(tables[c], c)
+		},
+		"tag": func(c *schema.Column) string {
+			return structTag(tableGormTag(tables[c], c))
+		},
+	})
+
+	buf := new(bytes.Buffer)
+	err = tmpl.Execute(buf, TemplateData{
+		Header:  GeneratedHeader,
+		Package: pkg,
+		Imports: Imports(s),
+		Tables:  s.Tables,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Imports returns the sorted packages used by the models of s.
+func Imports(s *schema.Schema) []string {
+	set := map[string]struct{}{}
+	for _, t := range s.Tables {
+		for _, c := range t.Columns {
+			if goType := LookupType(c.Type); goType.Import != "" {
+				set[goType.Import] = struct{}{}
+			}
+		}
+	}
+	importList := make([]string, 0, len(set))
+	for _import := range set {
+		importList = append(importList, _import)
+	}
+	sort.Strings(importList)
+	return importList
+}
+
+// tableGormTag is TableGormTag of a column of the rendered schema,
+// or GormTag of a column the template made up.
+func tableGormTag(t *schema.Table, c *schema.Column) string {
+	if t == nil {
+		return GormTag(c)
+	}
+	return TableGormTag(t, c)
+}
@@ -0,0 +1,305 @@
+package convert
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Statement is a statement of a sql script, like the mysql client splits it.
+type Statement struct {
+	// Text is the statement without its delimiter, including leading comments.
+	Text string
+	// Offset is the byte offset of Text in the script.
+	Offset int
+	// Delimiter ends the statement, empty for a statement without one, e.g. the last.
+	Delimiter string
+}
+
+// Keyword returns the first word of the statement in upper case,
+// skipping comments, empty if there is none, e.g. the statement is a comment.
+func (s Statement) Keyword() string {
+	text := s.Text
+	for {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		switch {
+		case strings.HasPrefix(text, "/*"):
+			end := strings.Index(text, "*/")
+			if end < 0 {
+				return ""
+			}
+			text = text[end+2:]
+		case strings.HasPrefix(text, "#") || isDashComment(text):
+			end := strings.IndexByte(text, '\n')
+			if end < 0 {
+				return ""
+			}
+			text = text[end+1:]
+		default:
+			end := strings.IndexFunc(text, func(r rune) bool {
+				return !unicode.IsLetter(r) && r != '_'
+			})
+			if end < 0 {
+				end = len(text)
+			}
+			return strings.ToUpper(text[:end])
+		}
+	}
+}
+
+// Split splits a script into statements by the delimiter, `;` by default.
+// Delimiters in strings, quoted names and comments do not split,
+// and DELIMITER commands change the delimiter like the mysql client does,
+// they are not statements. Unlike the mysql client, the BEGIN ... END body
+// of a trigger, routine or event is not split when the delimiter is not changed,
+// and a CREATE starting a line after the body, e.g. after RETURN expr of a function
+// missing its delimiter, starts the next statement instead of being swallowed.
+func Split(script string) []Statement {
+	result := make([]Statement, 0)
+	delimiter := ";"
+	start := 0
+	// empty is set until the statement has anything but spaces and comments
+	empty := true
+	var b body
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(script, i)
+			empty = false
+		case isComment(script, i):
+			i = skipComment(script, i)
+		case b.depth == 0 && strings.HasPrefix(script[i:], delimiter):
+			result = append(result, Statement{Text: script[start:i], Offset: start, Delimiter: delimiter})
+			i += len(delimiter)
+			start, empty, b = i, true, body{}
+		case empty && isDelimiterCommand(script[i:]):
+			// the statement so far is spaces and comments
+			if start < i {
+				result = append(result, Statement{Text: script[start:i], Offset: start})
+			}
+			end := skipLine(script, i)
+			if d := strings.TrimSpace(script[i+len("DELIMITER") : end]); d != "" {
+				delimiter = d
+			}
+			i = end
+			start = i
+		case isWordStart(script, i):
+			end := wordEnd(script, i)
+			w := strings.ToUpper(script[i:end])
+			if w == "CREATE" && b.done && isLineStart(script, i) {
+				result = append(result, Statement{Text: script[start:i], Offset: start})
+				start, b = i, body{}
+			}
+			b.word(w, script[end:])
+			i, empty = end, false
+		default:
+			if !unicode.IsSpace(rune(c)) {
+				empty = false
+			}
+			i++
+		}
+	}
+	if start < len(script) {
+		result = append(result, Statement{Text: script[start:], Offset: start})
+	}
+	return result
+}
+
+// isDashComment reports whether s starts with a -- comment,
+// the dashes are followed by a space or the end of the line in mysql.
+func isDashComment(s string) bool {
+	return strings.HasPrefix(s, "--") && (len(s) == 2 || s[2] == ' ' || s[2] == '\t' || s[2] == '\r' || s[2] == '\n')
+}
+
+// body follows the words of a statement to find the BEGIN ... END body
+// of CREATE or ALTER TRIGGER, PROCEDURE, FUNCTION and EVENT.
+type body struct {
+	first string
+	// routine is set once the statement is known to create or alter one
+	routine, other bool
+	// depth is the number of blocks not ended yet, BEGIN and CASE end with END
+	depth int
+	// done is set once the body has started, RETURN of a function, or has ended, END of a block
+	done bool
+}
+
+// word is called with each word of the statement in upper case and the script after it.
+func (b *body) word(w string, rest string) {
+	switch {
+	case b.first == "":
+		b.first = w
+	case b.first != "CREATE" && b.first != "ALTER", b.other:
+		// not a routine
+	case !b.routine:
+		switch w {
+		case "TRIGGER", "PROCEDURE", "FUNCTION", "EVENT":
+			b.routine = true
+		case "TABLE", "VIEW", "INDEX", "DATABASE", "SCHEMA", "USER", "SERVER", "TABLESPACE":
+			b.other = true
+		}
+	case w == "RETURN" && b.depth == 0:
+		b.done = true
+	case w == "BEGIN" || w == "CASE":
+		b.depth++
+	case w == "END" && b.depth > 0:
+		// END IF, END LOOP ... end blocks not counted
+		switch nextWord(rest) {
+		case "IF", "LOOP", "WHILE", "REPEAT":
+		default:
+			b.depth--
+			b.done = b.done || b.depth == 0
+		}
+	}
+}
+
+// nextWord returns the word at the start of s after spaces in upper case.
+func nextWord(s string) string {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	return strings.ToUpper(s[:wordEnd(s, 0)])
+}
+
+// wordEnd returns the offset after the word at i of s.
+func wordEnd(s string, i int) int {
+	for i < len(s) && isWordByte(s[i]) {
+		i++
+	}
+	return i
+}
+
+// isWordStart reports whether a keyword or an unquoted name starts at i of s,
+// not a part of a name, a qualified name or a variable.
+func isWordStart(s string, i int) bool {
+	c := s[i]
+	if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_') {
+		return false
+	}
+	return i == 0 || !isWordByte(s[i-1]) && s[i-1] != '.' && s[i-1] != '@'
+}
+
+func isWordByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '_' || c == '$' || c >= utf8.RuneSelf
+}
+
+// isLineStart reports whether only spaces are before i on its line of s.
+func isLineStart(s string, i int) bool {
+	line := s[strings.LastIndexByte(s[:i], '\n')+1 : i]
+	return strings.TrimSpace(line) == ""
+}
+
+func isDelimiterCommand(s string) bool {
+	const command = "DELIMITER"
+	return len(s) > len(command) && strings.EqualFold(s[:len(command)], command) &&
+		(s[len(command)] == ' ' || s[len(command)] == '\t')
+}
+
+// isComment reports whether a --, # or /* */ comment starts at i of s.
+func isComment(s string, i int) bool {
+	return s[i] == '#' || isDashComment(s[i:]) || strings.HasPrefix(s[i:], "/*")
+}
+
+// skipComment returns the offset after the comment starting at i.
+func skipComment(s string, i int) int {
+	if !strings.HasPrefix(s[i:], "/*") {
+		return skipLine(s, i)
+	}
+	if end := strings.Index(s[i+2:], "*/"); end >= 0 {
+		return i + end + 4
+	}
+	return len(s)
+}
+
+// skipQuoted returns the offset after the quoted string starting at i,
+// backslash escapes and doubled quotes are skipped.
+func skipQuoted(s string, i int) int {
+	quote := s[i]
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// skipLine returns the offset after the end of the line of i.
+func skipLine(s string, i int) int {
+	if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+		return i + end + 1
+	}
+	return len(s)
+}
+
+// objects are the kinds of objects a statement creates, alters, drops or renames, see Kind.
+var objects = map[string]bool{
+	"TABLE":      true,
+	"INDEX":      true,
+	"VIEW":       true,
+	"TRIGGER":    true,
+	"PROCEDURE":  true,
+	"PROC":       true,
+	"FUNCTION":   true,
+	"EVENT":      true,
+	"DATABASE":   true,
+	"SCHEMA":     true,
+	"USER":       true,
+	"ROLE":       true,
+	"SERVER":     true,
+	"TABLESPACE": true,
+	"SEQUENCE":   true,
+	"TYPE":       true,
+	"DOMAIN":     true,
+}
+
+// Kind returns the keywords telling what the statement does: its first keyword and,
+// for CREATE, ALTER, DROP and RENAME, the kind of object after the modifiers,
+// e.g. CREATE TABLE for CREATE TEMPORARY TABLE and CREATE INDEX for CREATE UNIQUE INDEX.
+func (s Statement) Kind() string {
+	keyword := s.Keyword()
+	switch keyword {
+	case "CREATE", "ALTER", "DROP", "RENAME":
+	default:
+		return keyword
+	}
+	// the object follows a few modifiers, e.g. CREATE ALGORITHM=MERGE DEFINER=x SQL SECURITY INVOKER VIEW
+	text := s.Text
+	for i, words := 0, 0; i < len(text) && words < 10; {
+		c := text[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(text, i)
+		case isComment(text, i):
+			i = skipComment(text, i)
+		case isWordStart(text, i):
+			end := wordEnd(text, i)
+			if w := strings.ToUpper(text[i:end]); objects[w] {
+				return keyword + " " + w
+			}
+			i, words = end, words+1
+		default:
+			i++
+		}
+	}
+	return keyword
+}
+
+// DefaultStatements are the kinds of statements parsed by default, see Statement.Kind,
+// those which change tables. Others, e.g. the data of a dump, are skipped without being parsed.
+var DefaultStatements = []string{
+	"CREATE TABLE",
+	"CREATE INDEX",
+	"ALTER TABLE",
+	"DROP TABLE",
+	"DROP INDEX",
+	"RENAME TABLE",
+	"USE",
+	// the types and comments of PostgreSQL
+	"CREATE TYPE",
+	"CREATE DOMAIN",
+	"COMMENT",
+}
@@ -0,0 +1,77 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{"a; b;", []string{"a", " b"}},
+		{"a 'x;\\';y'; b `c;`", []string{"a 'x;\\';y'", " b `c;`"}},
+		{"-- c;\n# d;\n/* e; */ a;", []string{"-- c;\n# d;\n/* e; */ a"}},
+		{"a--b; c", []string{"a--b", " c"}},
+		{"DELIMITER ;;\nCREATE TRIGGER t BEGIN SET x = 1; END;;\nDELIMITER ;\nb;",
+			[]string{"CREATE TRIGGER t BEGIN SET x = 1; END", "b"}},
+		{"SET x = 'DELIMITER ;;'; b;", []string{"SET x = 'DELIMITER ;;'", " b"}},
+		{"CREATE TRIGGER t BEGIN IF a THEN SET x = CASE WHEN b THEN 1 END; END IF; END; b; BEGIN; c",
+			[]string{"CREATE TRIGGER t BEGIN IF a THEN SET x = CASE WHEN b THEN 1 END; END IF; END", " b", " BEGIN", " c"}},
+		{"CREATE TABLE event (begin int); b", []string{"CREATE TABLE event (begin int)", " b"}},
+		{"CREATE FUNCTION f() RETURNS int RETURN 1\n-- c\nCREATE TABLE t (a int); b",
+			[]string{"CREATE FUNCTION f() RETURNS int RETURN 1\n-- c\n", "CREATE TABLE t (a int)", " b"}},
+		{"CREATE PROCEDURE p() BEGIN\n  CREATE TABLE t (a int);\nEND\nCREATE TABLE u (a int); b",
+			[]string{"CREATE PROCEDURE p() BEGIN\n  CREATE TABLE t (a int);\nEND\n", "CREATE TABLE u (a int)", " b"}},
+		{"CREATE PROCEDURE p()\nCREATE TABLE t (a int); b", []string{"CREATE PROCEDURE p()\nCREATE TABLE t (a int)", " b"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, s := range Split(tt.script) {
+			if s.Keyword() != "" {
+				got = append(got, s.Text)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.script, got, tt.want)
+		}
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	script := "INSERT INTO t VALUES ('é;');\nDELIMITER ;;\nCREATE TABLE é (a int);;\nDELIMITER ;\n" +
+		"CREATE TABLE é (a int); CREATE TABLE b (a int,)"
+	err := NewListener(Option{}).Parse("x.sql", script)
+	list, ok := err.(ErrorList)
+	if !ok || len(list) != 1 {
+		t.Fatalf("Parse = %v, want one error", err)
+	}
+	e, ok := list[0].(*SyntaxError)
+	if !ok || e.Line != 5 || e.Column != 46 {
+		t.Errorf("error = %v, want at 5:46", list[0])
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{"create temporary table t (a int)", "CREATE TABLE"},
+		{"CREATE OR REPLACE TABLE t (a int)", "CREATE TABLE"},
+		{"CREATE UNIQUE INDEX i ON t (a)", "CREATE INDEX"},
+		{"ALTER ONLINE IGNORE TABLE t ADD a int", "ALTER TABLE"},
+		{"/* x */ DROP TEMPORARY TABLE IF EXISTS t", "DROP TABLE"},
+		{"CREATE DEFINER=`table`@`%` TRIGGER tr BEFORE INSERT ON t FOR EACH ROW SET x = 1", "CREATE TRIGGER"},
+		{"CREATE ALGORITHM=MERGE SQL SECURITY INVOKER VIEW v AS SELECT * FROM t", "CREATE VIEW"},
+		{"RENAME TABLE a TO b", "RENAME TABLE"},
+		{"select * from t", "SELECT"},
+		{"/*!40101 SET NAMES utf8 */", ""},
+	}
+	for _, tt := range tests {
+		if got := (Statement{Text: tt.statement}).Kind(); got != tt.want {
+			t.Errorf("Kind(%q) = %q, want %q", tt.statement, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package convert
+
+import (
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// sqlite reads the DDL of SQLite, e.g. written by .schema of the sqlite3 shell, see frontend.
+type sqlite struct{}
+
+func (sqlite) syntax() dialectSyntax {
+	return dialectSyntax{
+		quotes: map[byte]byte{'"': '"', '[': ']', '`': '`'},
+	}
+}
+
+// sqliteTypes maps the names of SQLite types to the MySQL types they are used like,
+// e.g. the drivers read timestamp columns as times.
+var sqliteTypes = map[string]string{
+	"timestamp":         "datetime",
+	"double precision":  "double",
+	"unsigned big int":  "bigint",
+	"varying character": "varchar",
+	"native character":  "char",
+	"nvarchar":          "varchar",
+	"nchar":             "char",
+	"clob":              "text",
+}
+
+// sqliteAffinity returns the affinity of a column declared with the type name,
+// see https://www.sqlite.org/datatype3.html#determination_of_column_affinity.
+func sqliteAffinity(name string) string {
+	switch {
+	case strings.Contains(name, "int"):
+		return "integer"
+	case strings.Contains(name, "char"), strings.Contains(name, "clob"), strings.Contains(name, "text"):
+		return "text"
+	case strings.Contains(name, "blob"), name == "":
+		return "blob"
+	case strings.Contains(name, "real"), strings.Contains(name, "floa"), strings.Contains(name, "doub"):
+		return "real"
+	}
+	return "numeric"
+}
+
+// setType sets the type of col. Any name is a type in SQLite: a name the type map does not know
+// is named by its affinity if it is INTEGER or REAL, e.g. floating point is an integer like
+// SQLite reads it, and kept as a string like other unknown types if it is TEXT or NUMERIC.
+// A column without a type is a blob.
+func (sqlite) setType(p *ddlParser, col *schema.Column, t sqlType) {
+	name := t.name
+	_, known := typeMap[name]
+	switch affinity := sqliteAffinity(name); {
+	case sqliteTypes[name] != "":
+		name = sqliteTypes[name]
+	case known:
+	case affinity == "integer", affinity == "real", name == "":
+		name = affinity
+	}
+	col.Type = schema.DataType{
+		Name:   name,
+		Length: t.length(0),
+		Scale:  t.length(1),
+		Raw:    t.raw,
+	}
+}
+
+func (sqlite) setDefault(col *schema.Column, value string) {
+	col.Default = &value
+}
+
+// columnConstraint reads AUTOINCREMENT of an INTEGER PRIMARY KEY, and the ON CONFLICT
+// clause of a NOT NULL, a PRIMARY KEY or a UNIQUE, which is skipped.
+func (sqlite) columnConstraint(p *ddlParser, col *schema.Column) bool {
+	switch {
+	case p.is("AUTOINCREMENT"):
+		if !col.PrimaryKey || !strings.EqualFold(col.Type.Raw, "integer") {
+			p.errorf("AUTOINCREMENT is only allowed on an INTEGER PRIMARY KEY")
+		}
+		p.pos++
+		col.AutoIncrement = true
+	case p.accept("ON", "CONFLICT"):
+		p.name()
+	default:
+		return false
+	}
+	return true
+}
+
+// The table options of SQLite, they are flags without a value.
+const (
+	sqliteWithoutRowID = "WITHOUT ROWID"
+	sqliteStrict       = "STRICT"
+)
+
+// sqliteStrictTypes are the types the columns of a STRICT table may have.
+var sqliteStrictTypes = []string{"INT", "INTEGER", "REAL", "TEXT", "BLOB", "ANY"}
+
+// tableOptions reads WITHOUT ROWID and STRICT. The INTEGER PRIMARY KEY of a rowid table
+// is an alias of the rowid, it auto increments whether or not it is declared AUTOINCREMENT.
+// An INTEGER PRIMARY KEY DESC column is not an alias, the DESC of the key of the table
+// is not told apart from it.
+func (sqlite) tableOptions(p *ddlParser, t *schema.Table) {
+	for p.isName() {
+		switch {
+		case p.accept("WITHOUT", "ROWID"):
+			t.SetOption(sqliteWithoutRowID, "")
+		case p.accept("STRICT"):
+			t.SetOption(sqliteStrict, "")
+		default:
+			p.errorf("unknown table option: %s", p.peek().text)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	pk := t.PrimaryKey()
+	_, withoutRowID := t.Option(sqliteWithoutRowID)
+	if withoutRowID {
+		if pk == nil {
+			p.errorf("PRIMARY KEY missing on table %s", t.Name)
+		}
+		for _, col := range t.Columns {
+			if col.AutoIncrement {
+				p.errorf("AUTOINCREMENT not allowed on WITHOUT ROWID tables")
+			}
+		}
+	} else if pk != nil && len(pk.Columns) == 1 && !pk.Columns[0].Desc {
+		if col := t.Column(pk.Columns[0].Name); col != nil && strings.EqualFold(col.Type.Raw, "integer") {
+			col.AutoIncrement = true
+		}
+	}
+	if _, strict := t.Option(sqliteStrict); strict {
+		for _, col := range t.Columns {
+			if col.Type.Raw == "" {
+				p.errorf("missing datatype for %s.%s", t.Name, col.Name)
+			}
+			if !contains(sqliteStrictTypes, col.Type.Raw) {
+				p.errorf("unknown datatype for %s.%s: \"%s\"", t.Name, col.Name, col.Type.Raw)
+			}
+		}
+	}
+}
+
+func (sqlite) statement(p *ddlParser, kind string) (func(), bool) {
+	return nil, false
+}
+
+// skip skips CREATE TRIGGER, its body has statements ending with ;.
+// The body ends with the END which does not end a CASE.
+func (sqlite) skip(p *ddlParser, kind string) bool {
+	if kind != "CREATE TRIGGER" {
+		return false
+	}
+	cases := 0
+	for !p.is("BEGIN") && p.peek().kind != tokenEOF {
+		p.pos++
+	}
+	for p.peek().kind != tokenEOF {
+		switch {
+		case p.is("CASE"):
+			cases++
+		case p.is("END") && cases > 0:
+			cases--
+		case p.is("END"):
+			p.pos++
+			return true
+		}
+		p.pos++
+	}
+	return true
+}
@@ -0,0 +1,257 @@
+package convert
+
+import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	gen "github.com/er1c-zh/sql-to-gorm/antlr4_gen"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// DROP TABLE, RENAME TABLE, CREATE TABLE ... LIKE and CREATE TABLE ... AS SELECT
+// change the set of tables parsed so far.
+
+func (l *Listener) EnterDropTable(ctx *gen.DropTableContext) {
+	for _, name := range ctx.Tables().(*gen.TablesContext).AllTableName() {
+		database, table := l.tableName(name)
+		if l.Schema.Remove(database, table) == nil && ctx.IfExists() == nil {
+			l.option.warnf("%s:%d: drop unknown table %s",
+				l.CurrentSource, name.GetStart().GetLine(), table)
+		}
+	}
+}
+
+func (l *Listener) EnterRenameTable(ctx *gen.RenameTableContext) {
+	for _, clause := range ctx.AllRenameTableClause() {
+		c := clause.(*gen.RenameTableClauseContext)
+		database, from := l.tableName(c.TableName(0))
+		_, to := tableName(c.TableName(1))
+		t := l.Schema.Lookup(database, from)
+		if t == nil {
+			l.option.warnf("%s:%d: rename unknown table %s",
+				l.CurrentSource, c.GetStart().GetLine(), from)
+			continue
+		}
+		l.renameTable(c, t, to)
+	}
+}
+
+// putTable adds t to the schema, a table with the same name is replaced
+// unless the statement has IF NOT EXISTS.
+func (l *Listener) putTable(t *schema.Table, ifNotExists bool) {
+	if old := l.Schema.Lookup(t.Database, t.Name); old != nil && ifNotExists {
+		return
+	}
+	if old := l.Schema.Put(t); old != nil {
+		l.option.warnf("duplicate table %s: defined in %s and %s, using the latter",
+			old.Name, old.Source, t.Source)
+	}
+}
+
+/////////////////////////////////////////////
+// create table like ////////////////////////
+/////////////////////////////////////////////
+
+// CREATE TABLE t LIKE src copies the columns and indexes of src,
+// foreign keys are not copied like mysql does.
+func (l *Listener) EnterCopyCreateTable(ctx *gen.CopyCreateTableContext) {
+	database, name := l.tableName(ctx.TableName(0))
+	fromDatabase, from := l.tableName(ctx.TableName(1))
+	src := l.Schema.Lookup(fromDatabase, from)
+	if src == nil {
+		l.option.warnf("%s:%d: create table %s like unknown table %s, skipped",
+			l.CurrentSource, ctx.GetStart().GetLine(), name, from)
+		return
+	}
+	t := src.Clone()
+	t.Name = name
+	t.Database = database
+	t.Source = l.CurrentSource
+	constraints := t.Constraints[:0]
+	for _, c := range t.Constraints {
+		if c.Type != schema.ConstraintForeignKey {
+			constraints = append(constraints, c)
+		}
+	}
+	t.Constraints = constraints
+	l.putTable(t, ctx.IfNotExists() != nil)
+}
+
+/////////////////////////////////////////////
+// create table as select ///////////////////
+/////////////////////////////////////////////
+
+func (l *Listener) EnterQueryCreateTable(ctx *gen.QueryCreateTableContext) {
+	if l.CurrentTable != nil {
+		l.Error(ctx, "table %s is not done", l.CurrentTable.Name)
+	}
+	database, name := l.tableName(ctx.TableName())
+	l.CurrentTable = &schema.Table{
+		Name:     name,
+		Database: database,
+		Dialect:  l.dialect(),
+		Source:   l.CurrentSource,
+	}
+	for _, option := range ctx.AllTableOption() {
+		l.addTableOption(option)
+	}
+}
+
+// ExitQueryCreateTable appends the columns selected from known tables
+// after the declared columns, warns about columns it can not infer.
+func (l *Listener) ExitQueryCreateTable(ctx *gen.QueryCreateTableContext) {
+	if l.CurrentTable == nil {
+		l.Error(ctx, "table done but not started")
+		return
+	}
+	t := l.CurrentTable
+	l.CurrentTable = nil
+	for _, col := range l.selectColumns(ctx.SelectStatement()) {
+		// a declared column takes the name of a selected column
+		if t.Column(col.Name) == nil {
+			t.Columns = append(t.Columns, col)
+		}
+	}
+	l.putTable(t, ctx.IfNotExists() != nil)
+}
+
+// selectSource is a table in the from clause of a select.
+type selectSource struct {
+	alias string
+	// table is nil if unknown, e.g. a sub query.
+	table *schema.Table
+}
+
+// selectColumns returns the columns of a select, inferred from the tables selected from.
+func (l *Listener) selectColumns(ctx gen.ISelectStatementContext) []*schema.Column {
+	var spec *gen.QuerySpecificationContext
+	switch c := ctx.(type) {
+	case *gen.SimpleSelectContext:
+		spec = c.QuerySpecification().(*gen.QuerySpecificationContext)
+	case *gen.ParenthesisSelectContext:
+		spec = querySpecification(c.QueryExpression())
+	}
+	if spec == nil {
+		l.option.warnf("%s:%d: can not infer columns of %s",
+			l.CurrentSource, ctx.GetStart().GetLine(), firstLine(originalText(ctx)))
+		return nil
+	}
+
+	var sources []selectSource
+	if spec.FromClause() != nil {
+		sources = l.selectSources(spec.FromClause().(*gen.FromClauseContext).TableSources(), nil)
+	}
+
+	result := make([]*schema.Column, 0)
+	addFrom := func(sources []selectSource) {
+		for _, source := range sources {
+			if source.table == nil {
+				l.option.warnf("%s:%d: can not infer columns of %s",
+					l.CurrentSource, spec.GetStart().GetLine(), source.alias)
+				continue
+			}
+			for _, col := range source.table.Columns {
+				result = append(result, selectedColumn(col, ""))
+			}
+		}
+	}
+
+	elements := spec.SelectElements().(*gen.SelectElementsContext)
+	if elements.GetStar() != nil {
+		addFrom(sources)
+	}
+	for _, element := range elements.AllSelectElement() {
+		switch e := element.(type) {
+		case *gen.SelectStarElementContext:
+			addFrom(findSource(sources, splitName(originalText(e.FullId()))))
+		case *gen.SelectColumnElementContext:
+			parts := splitName(originalText(e.FullColumnName()))
+			name := parts[len(parts)-1]
+			var col *schema.Column
+			for _, source := range findSource(sources, parts[:len(parts)-1]) {
+				if source.table == nil {
+					continue
+				}
+				if col = source.table.Column(name); col != nil {
+					break
+				}
+			}
+			if col == nil {
+				l.option.warnf("%s:%d: can not infer type of column %s",
+					l.CurrentSource, e.GetStart().GetLine(), originalText(e))
+				continue
+			}
+			result = append(result, selectedColumn(col, uid(e.Uid())))
+		default:
+			l.option.warnf("%s:%d: can not infer type of column %s",
+				l.CurrentSource, element.GetStart().GetLine(), originalText(element))
+		}
+	}
+	return result
+}
+
+// selectedColumn copies col as a column of a created table,
+// keys and auto increment are not kept.
+func selectedColumn(col *schema.Column, alias string) *schema.Column {
+	c := col.Clone()
+	if alias != "" {
+		c.Name = alias
+	}
+	c.PrimaryKey = false
+	c.Unique = false
+	c.AutoIncrement = false
+	return c
+}
+
+func querySpecification(ctx gen.IQueryExpressionContext) *gen.QuerySpecificationContext {
+	c := ctx.(*gen.QueryExpressionContext)
+	if c.QuerySpecification() != nil {
+		return c.QuerySpecification().(*gen.QuerySpecificationContext)
+	}
+	if c.QueryExpression() != nil {
+		return querySpecification(c.QueryExpression())
+	}
+	return nil
+}
+
+// selectSources collects the tables of a from clause, including joined tables.
+func (l *Listener) selectSources(tree antlr.Tree, sources []selectSource) []selectSource {
+	switch c := tree.(type) {
+	case *gen.AtomTableItemContext:
+		database, name := l.tableName(c.TableName())
+		alias := name
+		if c.GetAlias() != nil {
+			alias = uid(c.GetAlias())
+		}
+		return append(sources, selectSource{alias: alias, table: l.Schema.Lookup(database, name)})
+	case *gen.SubqueryTableItemContext:
+		return append(sources, selectSource{alias: uid(c.GetAlias())})
+	case gen.IExpressionContext:
+		return sources
+	}
+	for _, child := range tree.GetChildren() {
+		sources = l.selectSources(child, sources)
+	}
+	return sources
+}
+
+// findSource returns the sources named by qualifier, all sources if it is empty.
+func findSource(sources []selectSource, qualifier []string) []selectSource {
+	if len(qualifier) == 0 {
+		return sources
+	}
+	alias := qualifier[len(qualifier)-1]
+	for _, source := range sources {
+		if strings.EqualFold(source.alias, alias) {
+			return []selectSource{source}
+		}
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexAny(s, "\r\n"); i >= 0 {
+		return s[:i] + "..."
+	}
+	return s
+}
@@ -0,0 +1,233 @@
+package convert
+
+import (
+	_ "embed"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/er1c-zh/sql-to-gorm/ddl"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+//go:embed templates/gorm.tmpl
+var defaultTemplate string
+
+// TemplateData is the data passed to templates.
+type TemplateData struct {
+	// Header is the generated code header, see GeneratedHeader.
+	Header  string
+	Package string
+	// Imports are the packages used by the go types of the columns.
+	Imports []string
+	Tables  []*schema.Table
+}
+
+// TemplateFuncs are the funcs available in templates, structName, fieldName,
+// gormTag and tag are bound to the rendered schema by RenderTemplate.
+var TemplateFuncs = template.FuncMap{
+	"structName": func(t *schema.Table) string {
+		return GoName(t.Name)
+	},
+	"fieldName": func(c *schema.Column) string {
+		return GoName(c.Name)
+	},
+	"camel":  GoName,
+	"snake":  CamelToSnake,
+	"plural": plural,
+	"lower":  strings.ToLower,
+	"upper":  strings.ToUpper,
+	"join":   strings.Join,
+	"goType": func(c *schema.Column) string {
+		return LookupType(c.Type).Name
+	},
+	"gormTag": GormTag,
+	"tag": func(c *schema.Column) string {
+		return structTag(GormTag(c))
+	},
+	"comment": func(c *schema.Column) string {
+		if c.Comment == "" {
+			return oneLine(c.Name)
+		}
+		return oneLine(c.Comment)
+	},
+}
+
+// structTag returns the gorm struct tag of a column with the content gormTag as a go string literal,
+// a raw string unless the tag has a backquote or control characters.
+func structTag(gormTag string) string {
+	tag := "gorm:" + strconv.Quote(gormTag)
+	if strconv.CanBackquote(tag) {
+		return "`" + tag + "`"
+	}
+	return strconv.Quote(tag)
+}
+
+// oneLine returns s on one line without characters which can not be in go comments.
+func oneLine(s string) string {
+	s = strings.Join(strings.Fields(strings.ToValidUTF8(s, "")), " ")
+	return strings.Map(func(r rune) rune {
+		if r == '\uFEFF' || !unicode.IsPrint(r) && r != ' ' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// ParseTemplate parses the template files matching pattern,
+// the default template is used if pattern is empty.
+// The first file is executed, others may define templates it uses.
+func ParseTemplate(pattern string) (*template.Template, error) {
+	if pattern == "" {
+		return template.New("gorm").Funcs(TemplateFuncs).Parse(defaultTemplate)
+	}
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("template %s: no such file", pattern)
+	}
+	return template.New(filepath.Base(files[0])).Funcs(TemplateFuncs).ParseFiles(files...)
+}
+
+// GormTag builds the content of the gorm tag of c, e.g. column:id;type:bigint unsigned;primaryKey,
+// with the type, the primary key, auto increment, not null and the default, so that gorm
+// migrates the column and reverse reads it back as it was. autoIncrement is also set for a
+// tidb AUTO_RANDOM column and -> (read-only) for generated columns and the period columns
+// of a mariadb system-versioned table. The indexes of the column are added by TableGormTag.
+func GormTag(c *schema.Column) string {
+	return gormTag(c, "")
+}
+
+// gormTag is GormTag of a column of a table in dialect. The type of a dialect which is not
+// MySQL compatible is written as it is in the DDL, gorm migrates the column on that server.
+func gormTag(c *schema.Column, dialect string) string {
+	tagList := make([]string, 0, 4)
+	tagList = append(tagList, fmt.Sprintf("column:%s", c.Name))
+	typ := ddl.Type(c.Type)
+	if !MySQLCompatible(dialect) {
+		typ = c.Type.Raw
+	}
+	if typ != "" {
+		tagList = append(tagList, "type:"+tagValue(typ))
+	}
+	if c.PrimaryKey {
+		tagList = append(tagList, "primaryKey")
+	}
+	switch {
+	case c.AutoIncrement, autoRandom(c):
+		// like an auto increment column, the value of AUTO_RANDOM is generated on insert and read back
+		tagList = append(tagList, "autoIncrement")
+	case c.SystemTime != "", c.Generated != "":
+		// the server computes the column and the period of a system-versioned row, writes are refused
+		tagList = append(tagList, "->")
+	}
+	if !c.Nullable && !c.PrimaryKey && c.SystemTime == "" {
+		tagList = append(tagList, "not null")
+	}
+	if c.Default != nil {
+		tagList = append(tagList, "default:"+tagValue(*c.Default))
+	}
+	return strings.Join(tagList, ";")
+}
+
+// autoRandom reports whether c is a tidb AUTO_RANDOM column.
+func autoRandom(c *schema.Column) bool {
+	_, ok := c.Option(rewriteAutoRandom)
+	return ok
+}
+
+// TableGormTag is GormTag with the indexes of c in t, e.g. index:idx_name,priority:2,
+// and autoIncrement:false for a single integer primary key which does not auto increment,
+// gorm would make it auto increment. The primary key and indexes with an expression are left out.
+func TableGormTag(t *schema.Table, c *schema.Column) string {
+	tagList := []string{gormTag(c, t.Dialect)}
+	if pk := t.PrimaryKey(); pk != nil && len(pk.Columns) == 1 && strings.EqualFold(pk.Columns[0].Name, c.Name) &&
+		!c.AutoIncrement && !autoRandom(c) && c.Default == nil && LookupType(c.Type) == typeInt64 {
+		tagList = append(tagList, "autoIncrement:false")
+	}
+	for _, idx := range t.Indexes {
+		if idx.Kind == schema.IndexPrimary || idx.Functional() {
+			continue
+		}
+		for i, col := range idx.Columns {
+			if !strings.EqualFold(col.Name, c.Name) {
+				continue
+			}
+			key := "index"
+			if idx.Kind == schema.IndexUnique {
+				key = "uniqueIndex"
+			}
+			settings := []string{tagValue(idx.Name)}
+			if idx.Kind == schema.IndexFulltext || idx.Kind == schema.IndexSpatial {
+				settings = append(settings, "class:"+idx.Kind)
+			}
+			if len(idx.Columns) > 1 {
+				settings = append(settings, fmt.Sprintf("priority:%d", i+1))
+			}
+			if col.Length != nil {
+				settings = append(settings, fmt.Sprintf("length:%d", *col.Length))
+			}
+			if col.Desc {
+				settings = append(settings, "sort:desc")
+			}
+			tagList = append(tagList, key+":"+strings.Join(settings, ","))
+		}
+	}
+	return strings.Join(tagList, ";")
+}
+
+// tagValue escapes the separator of the settings of a gorm tag.
+func tagValue(s string) string {
+	return strings.ReplaceAll(s, ";", `\;`)
+}
+
+// SnakeToCamel converts user_info to UserInfo.
+func SnakeToCamel(src string) string {
+	l := strings.Split(src, "_")
+	for i := 0; i < len(l); i++ {
+		if len(l[i]) == 0 {
+			continue
+		}
+		leading := strings.ToUpper(l[i][0:1])
+		l[i] = leading + l[i][1:]
+	}
+	return strings.Join(l, "")
+}
+
+// CamelToSnake converts UserInfo to user_info and UserID to user_id.
+func CamelToSnake(src string) string {
+	buf := new(strings.Builder)
+	runes := []rune(src)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// split before an upper case letter unless inside an acronym, e.g. UserID -> user_id
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				buf.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// plural returns the english plural of a word, e.g. user -> users, category -> categories.
+func plural(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case lower == "":
+		return word
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return word + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return word[:len(word)-1] + "ies"
+	}
+	return word + "s"
+}
@@ -0,0 +1,51 @@
+package convert_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+)
+
+// TestParseTemplate renders the models with a template of two files,
+// the first one executed uses the template the second one defines.
+func TestParseTemplate(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a_models.tmpl": "package {{ .Package }}\n{{ range .Tables }}\ntype {{ structName . }} struct {\n" +
+			"{{- range .Columns }}\n{{ template \"field\" . }}\n{{- end }}\n}\n{{ end }}",
+		"b_field.tmpl": "{{ define \"field\" }}\t{{ fieldName . }} {{ goType . }} {{ tag . }}{{ end }}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tmpl, err := convert.ParseTemplate(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	option := convert.DefaultOption()
+	option.Warnf = nil
+	s, err := convert.Convert(strings.NewReader("CREATE TABLE `user_info` (`id` bigint NOT NULL, `name` varchar(64));"), option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := convert.RenderTemplate(s, buf, tmpl, convert.Option{Package: "dao"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "package dao\n\ntype UserInfo struct {\n" +
+		"\tId int64 `gorm:\"column:id;type:bigint;not null\"`\n" +
+		"\tName string `gorm:\"column:name;type:varchar(64)\"`\n}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("rendered %q, want %q", got, want)
+	}
+
+	if _, err := convert.ParseTemplate(filepath.Join(dir, "*.missing")); err == nil {
+		t.Errorf("ParseTemplate of no file succeeded")
+	}
+}
@@ -0,0 +1,16 @@
+-- Names which are not go identifiers as they are.
+
+CREATE TABLE `order-items` (
+  `id` int NOT NULL,
+  `user_id` int,
+  `UserId` int,
+  `1st_choice` varchar(10),
+  `type` varchar(10),
+  `价格` decimal(10,2),
+  `$$` int,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `Order Items` (
+  `id` int NOT NULL
+);
@@ -0,0 +1,26 @@
+-- mariadb sequences, system-versioned tables and invisible columns.
+CREATE SEQUENCE `invoice_seq` START WITH 1000 INCREMENT BY 1;
+
+CREATE TABLE `invoice` (
+  `id` bigint NOT NULL DEFAULT NEXT VALUE FOR `invoice_seq`,
+  `number` bigint NOT NULL DEFAULT nextval(`shop`.`number_seq`),
+  `copy` bigint NOT NULL DEFAULT (NEXT VALUE FOR invoice_seq),
+  `customer` varchar(64) NOT NULL,
+  `secret` varchar(64) DEFAULT NULL INVISIBLE,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `price` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `amount` decimal(10,2) NOT NULL,
+  `note` text WITHOUT SYSTEM VERSIONING,
+  `row_start` timestamp(6) GENERATED ALWAYS AS ROW START INVISIBLE,
+  `row_end` timestamp(6) GENERATED ALWAYS AS ROW END INVISIBLE,
+  PRIMARY KEY (`id`),
+  PERIOD FOR SYSTEM_TIME(`row_start`, `row_end`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 WITH SYSTEM VERSIONING;
+
+CREATE TABLE `audit` (
+  `id` int NOT NULL,
+  `state` varchar(16) NOT NULL WITH SYSTEM VERSIONING
+) ENGINE=InnoDB /*M!100301 PAGE_CHECKSUM=1 */;
@@ -0,0 +1,25 @@
+-- MySQL 8.0 DDL the 5.7 grammar does not know, see convert/mysql8.go.
+CREATE TABLE `account` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `email` varchar(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+  `password_hash` varchar(64) DEFAULT NULL INVISIBLE,
+  `nickname` varchar(32) NOT NULL DEFAULT '' VISIBLE,
+  `home` point NOT NULL SRID 4326,
+  `balance` decimal(10,2) NOT NULL DEFAULT (0),
+  `tags` json NOT NULL DEFAULT (json_array()),
+  `age` int CHECK (`age` >= 0) NOT ENFORCED,
+  `created_at` datetime NOT NULL DEFAULT (now()),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_email` ((lower(`email`))),
+  KEY `idx_created` (`id`, (date(`created_at`)) DESC) INVISIBLE,
+  KEY ((`balance` * 100)),
+  SPATIAL KEY `idx_home` (`home`),
+  CONSTRAINT `chk_balance` CHECK (`balance` >= 0) ENFORCED,
+  CONSTRAINT `chk_age` CHECK (`age` < 200) NOT ENFORCED
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE `account`
+  ADD COLUMN `note` text INVISIBLE,
+  ADD INDEX `idx_note` ((left(`note`, 10)));
+ALTER TABLE `account` ADD CONSTRAINT `chk_nickname` CHECK (`nickname` <> 'root') NOT ENFORCED;
+CREATE INDEX `idx_nickname` ON `account` ((upper(`nickname`)));
@@ -0,0 +1,124 @@
+--
+-- PostgreSQL database dump
+--
+
+\restrict 3dd0bd0bba6ab3c71d3e2b8cc9b5bf8a
+
+SET statement_timeout = 0;
+SET client_encoding = 'UTF8';
+SET standard_conforming_strings = on;
+SELECT pg_catalog.set_config('search_path', '', false);
+
+CREATE EXTENSION IF NOT EXISTS pgcrypto WITH SCHEMA public;
+
+CREATE TYPE public.order_status AS ENUM (
+    'pending',
+    'paid',
+    'shipped'
+);
+
+CREATE DOMAIN public.email AS character varying(320)
+    CONSTRAINT email_check CHECK (((VALUE)::text ~ '^[^@]+@[^@]+$'::text));
+
+CREATE FUNCTION public.touch_updated_at() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    NEW.updated_at := now();
+    RETURN NEW;
+END;
+$$;
+
+SET default_tablespace = '';
+
+CREATE TABLE public.users (
+    id integer NOT NULL,
+    email public.email NOT NULL,
+    name text,
+    "displayName" character varying(64) DEFAULT 'anonymous'::character varying NOT NULL,
+    balance numeric(12,2) DEFAULT 0.00 NOT NULL,
+    score double precision,
+    is_active boolean DEFAULT true NOT NULL,
+    avatar bytea,
+    tags text[] DEFAULT '{}'::text[],
+    settings jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp(3) without time zone,
+    birthday date
+);
+
+COMMENT ON TABLE public.users IS 'registered users';
+COMMENT ON COLUMN public.users.email IS 'login, unique';
+
+CREATE SEQUENCE public.users_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.users_id_seq OWNED BY public.users.id;
+
+ALTER TABLE ONLY public.users ALTER COLUMN id SET DEFAULT nextval('public.users_id_seq'::regclass);
+
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX users_email_key ON public.users USING btree (lower((email)::text));
+
+CREATE TRIGGER users_touch BEFORE UPDATE ON public.users FOR EACH ROW EXECUTE FUNCTION public.touch_updated_at();
+
+ALTER TABLE public.users OWNER TO app;
+
+-- a migration written by hand
+CREATE TABLE IF NOT EXISTS Orders (
+    ID BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+    user_id INT NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+    status public.order_status NOT NULL DEFAULT 'pending',
+    total NUMERIC(10, 2) NOT NULL CHECK (total >= 0),
+    items INTEGER[][],
+    note VARCHAR,
+    shipped_at TIMESTAMPTZ,
+    duration INTERVAL HOUR TO MINUTE,
+    weight REAL,
+    CONSTRAINT orders_note_check CHECK (length(note) < 1000) NOT VALID
+) WITH (fillfactor = 90);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS orders_user_id_idx ON orders (user_id, created DESC NULLS LAST) WHERE status <> 'shipped';
+
+ALTER TABLE orders
+    ADD COLUMN created timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS note VARCHAR,
+    ALTER COLUMN note TYPE text,
+    ALTER COLUMN weight SET DATA TYPE float(53) USING weight::float8,
+    DROP COLUMN IF EXISTS legacy,
+    RENAME COLUMN duration TO handling_time;
+
+CREATE TABLE line_items (
+    order_id bigint,
+    sku text COLLATE "C",
+    quantity smallint DEFAULT 1,
+    price money,
+    ratio float4,
+    code "char",
+    id integer GENERATED ALWAYS AS IDENTITY,
+    amount numeric GENERATED ALWAYS AS (quantity * 2) STORED,
+    PRIMARY KEY (order_id, sku),
+    FOREIGN KEY (order_id) REFERENCES orders (id) MATCH FULL ON UPDATE NO ACTION DEFERRABLE INITIALLY DEFERRED
+) PARTITION BY HASH (order_id);
+
+CREATE TABLE audit_log (LIKE line_items INCLUDING DEFAULTS, happened_at timestamptz);
+
+CREATE TEMP TABLE scratch AS SELECT 1 AS one;
+
+DROP TABLE IF EXISTS scratch, audit_log CASCADE;
+
+DO $body$
+BEGIN
+    RAISE NOTICE 'done; really';
+END
+$body$;
+
+\unrestrict 3dd0bd0bba6ab3c71d3e2b8cc9b5bf8a
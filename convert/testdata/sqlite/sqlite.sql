@@ -0,0 +1,71 @@
+PRAGMA foreign_keys=OFF;
+BEGIN TRANSACTION;
+CREATE TABLE IF NOT EXISTS "users" (
+	"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+	"email" VARCHAR(320) NOT NULL UNIQUE ON CONFLICT ABORT,
+	"name" TEXT COLLATE NOCASE,
+	"age" INT DEFAULT 0 CHECK (age >= 0),
+	"score" DOUBLE PRECISION,
+	"ratio" FLOATING POINT,
+	"balance" DECIMAL(10,2) DEFAULT '0.00',
+	"active" BOOLEAN NOT NULL DEFAULT 1,
+	"avatar" BLOB,
+	"settings" JSON DEFAULT '{}',
+	"created_at" DATETIME DEFAULT CURRENT_TIMESTAMP,
+	"updated_at" TIMESTAMP,
+	"birthday" DATE
+);
+INSERT INTO users VALUES(1,'a@example.com','a',30,NULL,NULL,'0.00',1,NULL,'{}','2024-01-01 00:00:00',NULL,NULL);
+DELETE FROM sqlite_sequence;
+INSERT INTO sqlite_sequence VALUES('users',1);
+CREATE TABLE posts (
+	id integer NOT NULL,
+	user_id UNSIGNED BIG INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	title NVARCHAR(200) NOT NULL,
+	body CLOB,
+	views,
+	published_at datetime,
+	PRIMARY KEY (id),
+	FOREIGN KEY (user_id) REFERENCES users (id) DEFERRABLE INITIALLY DEFERRED
+);
+CREATE INDEX idx_posts_user ON posts (user_id, published_at DESC) WHERE published_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS main.idx_posts_title ON posts (title COLLATE NOCASE);
+
+-- a rowid table whose INT PRIMARY KEY is not an alias of the rowid
+CREATE TABLE [tags] (
+	[id] INT PRIMARY KEY,
+	[name] TEXT NOT NULL
+);
+
+CREATE TABLE post_tags (
+	post_id INTEGER NOT NULL,
+	tag_id INTEGER NOT NULL,
+	PRIMARY KEY (post_id, tag_id)
+) WITHOUT ROWID;
+
+CREATE TABLE `settings` (
+	`key` TEXT PRIMARY KEY,
+	`value` ANY,
+	`version` INTEGER NOT NULL DEFAULT 1,
+	`size` INT AS (length(`value`)) STORED
+) STRICT, WITHOUT ROWID;
+
+CREATE TABLE events (
+	id INTEGER PRIMARY KEY DESC,
+	kind TEXT NOT NULL DEFAULT ('info'),
+	at REAL DEFAULT (julianday('now'))
+) STRICT;
+
+CREATE VIRTUAL TABLE posts_fts USING fts5(title, body, content='posts');
+
+CREATE TRIGGER posts_ai AFTER INSERT ON posts BEGIN
+	INSERT INTO posts_fts(rowid, title, body) VALUES (new.id, new.title, new.body);
+	UPDATE users SET age = CASE WHEN age IS NULL THEN 0 ELSE age END;
+END;
+
+CREATE VIEW recent_posts AS SELECT * FROM posts ORDER BY published_at DESC LIMIT 10;
+
+ALTER TABLE posts ADD COLUMN slug TEXT;
+ALTER TABLE posts RENAME COLUMN views TO view_count;
+ALTER TABLE tags RENAME TO labels;
+COMMIT;
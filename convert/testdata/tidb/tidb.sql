@@ -0,0 +1,27 @@
+-- SHOW CREATE TABLE of tidb, the attributes of tidb are in /*T! */ comments.
+CREATE TABLE `order` (
+  `id` bigint(20) NOT NULL /*T![auto_rand] AUTO_RANDOM(5) */,
+  `user_id` bigint(20) NOT NULL,
+  `amount` decimal(10,2) NOT NULL DEFAULT '0.00',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,
+  KEY `idx_user` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![auto_rand_base] AUTO_RANDOM_BASE=30001 */;
+
+CREATE TABLE `event_log` (
+  `id` bigint(20) NOT NULL,
+  `payload` json DEFAULT NULL,
+  PRIMARY KEY (`id`) /*T![clustered_index] NONCLUSTERED */
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T! SHARD_ROW_ID_BITS=4 PRE_SPLIT_REGIONS=2 */ /*T![placement] PLACEMENT POLICY=`p1` */;
+
+-- written by hand
+CREATE TABLE `session` (
+  `id` bigint PRIMARY KEY CLUSTERED AUTO_RANDOM,
+  `token` varchar(64) NOT NULL
+) SHARD_ROW_ID_BITS = 2 AUTO_ID_CACHE 1;
+
+CREATE TABLE `visit` (
+  `id` bigint AUTO_RANDOM(4, 54),
+  `path` varchar(255) NOT NULL,
+  PRIMARY KEY (`id`) CLUSTERED
+);
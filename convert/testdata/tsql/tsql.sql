@@ -0,0 +1,87 @@
+USE [Shop]
+GO
+/****** Object:  UserDefinedDataType [dbo].[Phone]    Script Date: 1/2/2024 10:00:00 AM ******/
+CREATE TYPE [dbo].[Phone] FROM [varchar](20) NULL
+GO
+SET ANSI_NULLS ON
+GO
+SET QUOTED_IDENTIFIER ON
+GO
+/****** Object:  Table [dbo].[Users]    Script Date: 1/2/2024 10:00:00 AM ******/
+CREATE TABLE [dbo].[Users](
+	[Id] [int] IDENTITY(1,1) NOT NULL,
+	[PublicId] [uniqueidentifier] ROWGUIDCOL NOT NULL,
+	[Email] [nvarchar](320) NOT NULL,
+	[DisplayName] [nvarchar](64) NULL,
+	[Bio] [nvarchar](max) NULL,
+	[Avatar] [varbinary](max) NULL,
+	[Phone] [dbo].[Phone] NULL,
+	[Balance] [money] NOT NULL,
+	[Discount] [smallmoney] NULL,
+	[Score] [float] NULL,
+	[Ratio] [float](24) NULL,
+	[IsActive] [bit] NOT NULL,
+	[CreatedAt] [datetime2](7) NOT NULL,
+	[LastSeen] [datetimeoffset](7) NULL,
+	[Birthday] [date] NULL,
+	[RowVersion] [timestamp] NOT NULL,
+ CONSTRAINT [PK_Users] PRIMARY KEY CLUSTERED
+(
+	[Id] ASC
+)WITH (PAD_INDEX = OFF, STATISTICS_NORECOMPUTE = OFF, IGNORE_DUP_KEY = OFF, ALLOW_ROW_LOCKS = ON, ALLOW_PAGE_LOCKS = ON, OPTIMIZE_FOR_SEQUENTIAL_KEY = OFF) ON [PRIMARY],
+ CONSTRAINT [UQ_Users_Email] UNIQUE NONCLUSTERED
+(
+	[Email] ASC
+)WITH (PAD_INDEX = OFF, STATISTICS_NORECOMPUTE = OFF) ON [PRIMARY]
+) ON [PRIMARY] TEXTIMAGE_ON [PRIMARY]
+GO
+ALTER TABLE [dbo].[Users] ADD  CONSTRAINT [DF_Users_PublicId]  DEFAULT (newsequentialid()) FOR [PublicId]
+GO
+ALTER TABLE [dbo].[Users] ADD  CONSTRAINT [DF_Users_Balance]  DEFAULT ((0)) FOR [Balance]
+GO
+ALTER TABLE [dbo].[Users] ADD  CONSTRAINT [DF_Users_IsActive]  DEFAULT ((1)) FOR [IsActive]
+GO
+ALTER TABLE [dbo].[Users] ADD  CONSTRAINT [DF_Users_CreatedAt]  DEFAULT (sysutcdatetime()) FOR [CreatedAt]
+GO
+/****** Object:  StoredProcedure [dbo].[GetUser]    Script Date: 1/2/2024 10:00:00 AM ******/
+CREATE PROCEDURE [dbo].[GetUser]
+	@Id int
+AS
+BEGIN
+	SET NOCOUNT ON;
+	CREATE TABLE #seen (Id int);
+	SELECT * FROM [dbo].[Users] WHERE [Id] = @Id;
+END
+GO
+EXEC sys.sp_addextendedproperty @name=N'MS_Description', @value=N'registered users' , @level0type=N'SCHEMA',@level0name=N'dbo', @level1type=N'TABLE',@level1name=N'Users'
+GO
+
+-- written by hand, without GO and ;
+CREATE TABLE dbo.Orders (
+	OrderID BIGINT IDENTITY NOT NULL PRIMARY KEY NONCLUSTERED,
+	UserID INT NOT NULL CONSTRAINT FK_Orders_Users REFERENCES dbo.Users (Id) ON DELETE CASCADE,
+	Status NVARCHAR(16) NOT NULL CONSTRAINT DF_Orders_Status DEFAULT N'pending',
+	Total DECIMAL(19, 4) NOT NULL CHECK (Total >= 0),
+	Note VARCHAR(MAX),
+	Quantity SMALLINT,
+	Price MONEY,
+	LineTotal AS (Quantity * Price) PERSISTED,
+	PlacedAt SMALLDATETIME DEFAULT GETDATE() NOT NULL,
+	INDEX IX_Orders_PlacedAt NONCLUSTERED (PlacedAt DESC)
+)
+CREATE NONCLUSTERED INDEX IX_Orders_User ON dbo.Orders (UserID ASC) INCLUDE (Total) WHERE Status <> 'shipped' WITH (ONLINE = ON)
+IF OBJECT_ID(N'dbo.Legacy', N'U') IS NOT NULL DROP TABLE dbo.Legacy
+ALTER TABLE dbo.Orders ADD Coupon NVARCHAR(32) NULL, Shipped BIT NOT NULL DEFAULT 0 WITH VALUES
+ALTER TABLE dbo.Orders ALTER COLUMN Note NVARCHAR(1000) NOT NULL
+ALTER TABLE [dbo].[Orders] WITH CHECK ADD CONSTRAINT [CK_Orders_Coupon] CHECK ((len([Coupon])>(3)))
+ALTER TABLE [dbo].[Orders] CHECK CONSTRAINT [CK_Orders_Coupon]
+GRANT SELECT ON dbo.Orders TO reporting;
+CREATE TABLE [dbo].[Order Items] (
+	[OrderID] bigint NOT NULL,
+	[Sku] char(12) NOT NULL,
+	[Qty] tinyint NOT NULL,
+	CONSTRAINT [PK_Order Items] PRIMARY KEY ([OrderID], [Sku]),
+	CONSTRAINT [FK_Items_Orders] FOREIGN KEY ([OrderID]) REFERENCES [dbo].[Orders] ([OrderID]) NOT FOR REPLICATION
+);
+EXEC sp_rename 'dbo.Orders.Coupon', 'CouponCode', 'COLUMN';
+GO
@@ -0,0 +1,187 @@
+package convert
+
+import (
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// tsql reads the DDL of SQL Server, e.g. scripted by Management Studio with GO
+// between the batches, see frontend.
+type tsql struct{}
+
+func (tsql) syntax() dialectSyntax {
+	return dialectSyntax{
+		quotes:    map[byte]byte{'[': ']', '"': '"'},
+		batches:   true,
+		wordStart: "@#",
+		columnWords: []string{
+			"IDENTITY", "ROWGUIDCOL", "SPARSE", "FILESTREAM", "MASKED", "ENCRYPTED", "WITH", "INDEX",
+		},
+		statementWords: []string{"CREATE", "ALTER", "DROP", "USE"},
+	}
+}
+
+// tsqlTypes maps the names of SQL Server types to the MySQL types they are equivalent to,
+// other types keep their name, e.g. xml and datetimeoffset, which is a time like timestamptz.
+var tsqlTypes = map[string]string{
+	"nvarchar":         "varchar",
+	"nchar":            "char",
+	"ntext":            "text",
+	"image":            "longblob",
+	"uniqueidentifier": "uuid",
+	"datetime2":        "datetime",
+	"smalldatetime":    "datetime",
+	"datetimeoffset":   "timestamptz",
+	"bit":              "boolean",
+}
+
+// setType sets the type of col. varchar(max) and nvarchar(max) are longtext, varbinary(max)
+// is longblob, money and smallmoney are decimals of scale 4, and timestamp, the rowversion,
+// is binary(8), not a time.
+func (tsql) setType(p *ddlParser, col *schema.Column, t sqlType) {
+	if t.name == "" {
+		p.errorf("mismatched input %s expecting a type", p.quote(p.peek()))
+	}
+	name, length, scale := t.name, t.length(0), t.length(1)
+	switch {
+	case len(t.args) == 1 && t.args[0] == "max":
+		if strings.HasSuffix(name, "binary") {
+			name = "longblob"
+		} else {
+			name = "longtext"
+		}
+	case name == "float":
+		// float(n) is real up to 24 bits of mantissa, float(53) without n
+		if length == nil || *length > 24 {
+			name = "double"
+		}
+		length = nil
+	case name == "money":
+		name, length, scale = "decimal", atoi("19"), atoi("4")
+	case name == "smallmoney":
+		name, length, scale = "decimal", atoi("10"), atoi("4")
+	case name == "timestamp", name == "rowversion":
+		name, length = "binary", atoi("8")
+	case name == "sysname":
+		name, length = "varchar", atoi("128")
+	case tsqlTypes[name] != "":
+		name = tsqlTypes[name]
+	default:
+		if userType, ok := p.l.types[name]; ok {
+			col.Type = userType
+			col.Type.Raw = t.raw
+			return
+		}
+	}
+	col.Type = schema.DataType{
+		Name:   name,
+		Length: length,
+		Scale:  scale,
+		Raw:    t.raw,
+	}
+}
+
+// setDefault sets the default of col without the parentheses Management Studio writes
+// around it, e.g. 0 of ((0)).
+func (tsql) setDefault(col *schema.Column, value string) {
+	for {
+		v := unparenthesize(value)
+		if v == value {
+			break
+		}
+		value = v
+	}
+	col.Default = &value
+}
+
+// columnConstraint reads IDENTITY[(seed, increment)], which auto increments, NOT FOR REPLICATION,
+// WITH VALUES of a default and an index of the column. ROWGUIDCOL, SPARSE, FILESTREAM,
+// masks and encryption are skipped.
+func (tsql) columnConstraint(p *ddlParser, col *schema.Column) bool {
+	switch {
+	case p.accept("IDENTITY"):
+		col.AutoIncrement = true
+		col.Nullable = false
+		if p.is("(") {
+			p.parenthesizedList()
+		}
+	case p.accept("NOT", "FOR", "REPLICATION"), p.accept("WITH", "VALUES"),
+		p.accept("ROWGUIDCOL"), p.accept("SPARSE"), p.accept("FILESTREAM"):
+	case p.accept("MASKED"), p.accept("ENCRYPTED"):
+		p.expect("WITH")
+		p.parenthesizedList()
+	case p.accept("INDEX"):
+		idx := &schema.Index{Name: p.name(), Kind: schema.IndexNormal, Clustered: p.clustered()}
+		idx.Columns = []*schema.IndexColumn{{Name: col.Name}}
+		p.l.addIndex(idx)
+	default:
+		return false
+	}
+	return true
+}
+
+// tableOptions skips the filegroups and the options of the table:
+// ON filegroup, TEXTIMAGE_ON filegroup, FILESTREAM_ON filegroup and WITH (options).
+func (tsql) tableOptions(p *ddlParser, t *schema.Table) {
+	for {
+		switch {
+		case p.accept("ON"), p.accept("TEXTIMAGE_ON"), p.accept("FILESTREAM_ON"):
+			p.name()
+			if p.is("(") {
+				// the column of a partition scheme
+				p.names()
+			}
+		case p.accept("WITH"):
+			p.parenthesizedList()
+		default:
+			return
+		}
+	}
+}
+
+func (fe tsql) statement(p *ddlParser, kind string) (func(), bool) {
+	if kind == "CREATE TYPE" {
+		return fe.createType(p), true
+	}
+	return nil, false
+}
+
+// skip skips the routines, views and triggers up to the end of the batch, their bodies
+// have statements which do not end them, e.g. CREATE TABLE of a temporary table.
+// GRANT, DENY and REVOKE end with ; or the batch, they may name CREATE TABLE.
+func (tsql) skip(p *ddlParser, kind string) bool {
+	switch kind {
+	case "CREATE PROC", "CREATE PROCEDURE", "CREATE FUNCTION", "CREATE TRIGGER", "CREATE VIEW",
+		"ALTER PROC", "ALTER PROCEDURE", "ALTER FUNCTION", "ALTER TRIGGER", "ALTER VIEW":
+		for t := p.peek(); t.kind != tokenEOF && !(t.kind == tokenEnd && t.text == "GO"); t = p.peek() {
+			p.pos++
+		}
+	case "GRANT", "DENY", "REVOKE":
+		for t := p.peek(); t.kind != tokenEOF && t.kind != tokenEnd; t = p.peek() {
+			p.pos++
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// createType reads CREATE TYPE name FROM type, the columns of the type have its type.
+// Table types are skipped.
+func (fe tsql) createType(p *ddlParser) func() {
+	p.expect("CREATE", "TYPE")
+	parts := p.qualifiedName()
+	if !p.accept("FROM") {
+		p.skip("")
+		return nil
+	}
+	col := &schema.Column{}
+	fe.setType(p, col, p.sqlType())
+	if !p.accept("NOT", "NULL") {
+		p.accept("NULL")
+	}
+	return func() {
+		p.l.types[strings.ToLower(parts[len(parts)-1])] = col.Type
+	}
+}
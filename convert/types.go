@@ -0,0 +1,124 @@
+package convert
+
+import (
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// GoType is the go type of a column.
+type GoType struct {
+	Name string
+	// Import is the package of the type, empty for builtin types.
+	Import string
+}
+
+var (
+	typeInt64  = GoType{Name: "int64"}
+	typeFloat  = GoType{Name: "float64"}
+	typeString = GoType{Name: "string"}
+	typeBool   = GoType{Name: "bool"}
+	typeTime   = GoType{Name: "time.Time", Import: "time"}
+	typeBytes  = GoType{Name: "[]byte"}
+)
+
+// typeMap maps sql type names to go types, types not listed are strings.
+var typeMap = map[string]GoType{
+	// integer
+	"tinyint":   typeInt64,
+	"smallint":  typeInt64,
+	"mediumint": typeInt64,
+	"middleint": typeInt64,
+	"int":       typeInt64,
+	"integer":   typeInt64,
+	"bigint":    typeInt64,
+	"int1":      typeInt64,
+	"int2":      typeInt64,
+	"int3":      typeInt64,
+	"int4":      typeInt64,
+	"int8":      typeInt64,
+	"bit":       typeInt64,
+	"serial":    typeInt64,
+	// real
+	"real":    typeFloat,
+	"double":  typeFloat,
+	"float":   typeFloat,
+	"float4":  typeFloat,
+	"float8":  typeFloat,
+	"decimal": typeFloat,
+	"dec":     typeFloat,
+	"fixed":   typeFloat,
+	"numeric": typeFloat,
+	// time
+	"timestamp": typeInt64,
+	"datetime":  typeTime,
+	"date":      typeTime,
+	"year":      typeTime,
+	// postgresql
+	"timestamptz": typeTime,
+	"bytea":       typeBytes,
+	// bool
+	"bool":    typeBool,
+	"boolean": typeBool,
+}
+
+// LookupType returns the go type of a sql type.
+func LookupType(t schema.DataType) GoType {
+	if goType, ok := typeMap[t.Name]; ok {
+		return goType
+	}
+	return typeString
+}
+
+// sqlTypeMap maps go types back to sql types, the inverse of typeMap.
+// Only the name is used, e.g. varchar gets a default length.
+var sqlTypeMap = map[string]string{
+	"int64":     "bigint",
+	"int":       "bigint",
+	"int32":     "int",
+	"int16":     "smallint",
+	"int8":      "tinyint",
+	"uint64":    "bigint",
+	"uint":      "bigint",
+	"uint32":    "int",
+	"uint16":    "smallint",
+	"uint8":     "tinyint",
+	"float64":   "double",
+	"float32":   "float",
+	"string":    "varchar",
+	"bool":      "bool",
+	"time.Time": "datetime",
+	"[]byte":    "blob",
+	"[]uint8":   "blob",
+	// nullable types
+	"sql.NullString":  "varchar",
+	"sql.NullInt64":   "bigint",
+	"sql.NullInt32":   "int",
+	"sql.NullInt16":   "smallint",
+	"sql.NullByte":    "tinyint",
+	"sql.NullFloat64": "double",
+	"sql.NullBool":    "bool",
+	"sql.NullTime":    "datetime",
+	"gorm.DeletedAt":  "datetime",
+}
+
+// DefaultVarcharLength is the length of varchar columns reversed from strings.
+const DefaultVarcharLength = 255
+
+// ReverseType returns the sql type of a go type like time.Time or *int64,
+// ok is false if the type is unknown.
+func ReverseType(goType string) (t schema.DataType, ok bool) {
+	name, ok := sqlTypeMap[strings.TrimPrefix(goType, "*")]
+	if !ok {
+		return schema.DataType{}, false
+	}
+	t = schema.DataType{
+		Name:     name,
+		Unsigned: strings.HasPrefix(goType, "uint") || strings.HasPrefix(goType, "*uint"),
+	}
+	if name == "varchar" {
+		length := DefaultVarcharLength
+		t.Length = &length
+	}
+	return t, true
+}
@@ -0,0 +1,280 @@
+// Package ddl writes MySQL statements from the schema model.
+package ddl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// Write writes a CREATE TABLE statement for each table of s.
+func Write(w io.Writer, s *schema.Schema) error {
+	for i, t := range s.Tables {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, CreateTable(t)+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateTable returns the CREATE TABLE statement of t, ending with a semicolon.
+func CreateTable(t *schema.Table) string {
+	definitions := make([]string, 0, len(t.Columns)+len(t.Indexes)+len(t.Constraints))
+	var rowStart, rowEnd string
+	for _, c := range t.Columns {
+		definitions = append(definitions, Column(c))
+		switch c.SystemTime {
+		case "ROW START":
+			rowStart = c.Name
+		case "ROW END":
+			rowEnd = c.Name
+		}
+	}
+	if rowStart != "" && rowEnd != "" {
+		definitions = append(definitions, fmt.Sprintf("PERIOD FOR SYSTEM_TIME(%s, %s)", Quote(rowStart), Quote(rowEnd)))
+	}
+	for _, idx := range t.Indexes {
+		definitions = append(definitions, index(idx, mysqlCompatible(t.Dialect)))
+	}
+	for _, c := range t.Constraints {
+		definitions = append(definitions, Constraint(c))
+	}
+
+	buf := new(strings.Builder)
+	buf.WriteString(fmt.Sprintf("CREATE TABLE %s (\n  ", Quote(t.Name)))
+	buf.WriteString(strings.Join(definitions, ",\n  "))
+	buf.WriteString("\n)")
+	if options := TableOptions(t); options != "" {
+		buf.WriteString(" " + options)
+	}
+	buf.WriteString(";")
+	return buf.String()
+}
+
+// Column returns the definition of c, e.g. `id` bigint NOT NULL AUTO_INCREMENT.
+func Column(c *schema.Column) string {
+	parts := []string{Quote(c.Name), Type(c.Type)}
+	if srid, ok := c.Option("SRID"); ok {
+		parts = append(parts, "SRID "+srid)
+	}
+	if c.Collation != "" {
+		parts = append(parts, "COLLATE "+c.Collation)
+	}
+	if c.Generated != "" {
+		kind := "VIRTUAL"
+		if c.Stored {
+			kind = "STORED"
+		}
+		parts = append(parts, fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", c.Generated, kind))
+	}
+	if c.SystemTime != "" {
+		// the period columns of a system-versioned table are never null
+		parts = append(parts, "GENERATED ALWAYS AS "+c.SystemTime)
+	} else if !c.Nullable {
+		parts = append(parts, "NOT NULL")
+	} else if c.Generated == "" && !c.PrimaryKey {
+		parts = append(parts, "NULL")
+	}
+	if c.Default != nil {
+		parts = append(parts, "DEFAULT "+*c.Default)
+	}
+	if c.OnUpdate != "" {
+		parts = append(parts, "ON UPDATE "+c.OnUpdate)
+	}
+	if c.AutoIncrement {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if bits, ok := c.Option("AUTO_RANDOM"); ok {
+		parts = append(parts, "/*T![auto_rand] AUTO_RANDOM("+bits+") */")
+	}
+	if c.Invisible {
+		parts = append(parts, "INVISIBLE")
+	}
+	if c.Versioning != "" {
+		parts = append(parts, c.Versioning+" SYSTEM VERSIONING")
+	}
+	if c.Comment != "" {
+		parts = append(parts, "COMMENT "+String(c.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Type returns the sql spelling of t, e.g. decimal(10,2) unsigned.
+func Type(t schema.DataType) string {
+	buf := new(strings.Builder)
+	buf.WriteString(t.Name)
+	switch {
+	case len(t.Values) > 0:
+		values := make([]string, 0, len(t.Values))
+		for _, v := range t.Values {
+			values = append(values, String(v))
+		}
+		buf.WriteString("(" + strings.Join(values, ",") + ")")
+	case t.Length != nil && t.Scale != nil:
+		buf.WriteString(fmt.Sprintf("(%d,%d)", *t.Length, *t.Scale))
+	case t.Length != nil:
+		buf.WriteString(fmt.Sprintf("(%d)", *t.Length))
+	}
+	if t.Unsigned {
+		buf.WriteString(" unsigned")
+	}
+	if t.Zerofill {
+		buf.WriteString(" zerofill")
+	}
+	if t.Charset != "" {
+		buf.WriteString(" CHARACTER SET " + t.Charset)
+	}
+	return buf.String()
+}
+
+// Index returns the definition of idx of a MySQL compatible table, e.g. KEY `idx_name` (`name`).
+// CLUSTERED and NONCLUSTERED are written in the comment tidb reads.
+func Index(idx *schema.Index) string {
+	return index(idx, true)
+}
+
+// index returns the definition of idx, CLUSTERED and NONCLUSTERED are written
+// in the comment tidb reads, or in a plain comment if the table is not MySQL compatible,
+// e.g. of SQL Server, so tidb does not apply them.
+func index(idx *schema.Index, tidb bool) string {
+	var prefix string
+	switch idx.Kind {
+	case schema.IndexPrimary:
+		prefix = "PRIMARY KEY"
+	case schema.IndexUnique:
+		prefix = "UNIQUE KEY " + Quote(idx.Name)
+	case schema.IndexFulltext, schema.IndexSpatial:
+		prefix = idx.Kind + " KEY " + Quote(idx.Name)
+	default:
+		prefix = "KEY " + Quote(idx.Name)
+	}
+	columns := make([]string, 0, len(idx.Columns))
+	for _, c := range idx.Columns {
+		column := Quote(c.Name)
+		if c.Expression != "" {
+			column = "(" + c.Expression + ")"
+		}
+		if c.Length != nil {
+			column += fmt.Sprintf("(%d)", *c.Length)
+		}
+		if c.Desc {
+			column += " DESC"
+		}
+		columns = append(columns, column)
+	}
+	result := fmt.Sprintf("%s (%s)", prefix, strings.Join(columns, ","))
+	if idx.Using != "" {
+		result += " USING " + idx.Using
+	}
+	switch {
+	case idx.Clustered == "":
+	case tidb:
+		result += " /*T![clustered_index] " + idx.Clustered + " */"
+	default:
+		result += " /* " + idx.Clustered + " */"
+	}
+	if idx.Comment != "" {
+		result += " COMMENT " + String(idx.Comment)
+	}
+	return result
+}
+
+// mysqlCompatible reports whether the tables of dialect are read like mysql ones,
+// see schema.Table.Dialect.
+func mysqlCompatible(dialect string) bool {
+	switch dialect {
+	case "", "mariadb", "tidb":
+		return true
+	}
+	return false
+}
+
+// Constraint returns the definition of a foreign key or check constraint.
+func Constraint(c *schema.Constraint) string {
+	var prefix string
+	if c.Name != "" {
+		prefix = "CONSTRAINT " + Quote(c.Name) + " "
+	}
+	if c.Type == schema.ConstraintCheck {
+		if c.NotEnforced {
+			return prefix + fmt.Sprintf("CHECK (%s) NOT ENFORCED", c.Check)
+		}
+		return prefix + fmt.Sprintf("CHECK (%s)", c.Check)
+	}
+	result := prefix + fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		QuoteList(c.Columns), Quote(c.RefTable), QuoteList(c.RefColumns))
+	if c.OnDelete != "" {
+		result += " ON DELETE " + c.OnDelete
+	}
+	if c.OnUpdate != "" {
+		result += " ON UPDATE " + c.OnUpdate
+	}
+	return result
+}
+
+// TableOptions returns the options of t, e.g. ENGINE=InnoDB DEFAULT CHARSET=utf8mb4.
+func TableOptions(t *schema.Table) string {
+	options := make([]string, 0, len(t.Options))
+	var sqliteOptions []string
+	hasComment := false
+	for _, o := range t.Options {
+		switch o.Name {
+		case "COMMENT":
+			hasComment = true
+			options = append(options, "COMMENT="+String(o.Value))
+		case "CHARSET", "COLLATE":
+			options = append(options, "DEFAULT "+o.Name+"="+o.Value)
+		case "SHARD_ROW_ID_BITS", "PRE_SPLIT_REGIONS":
+			options = append(options, "/*T! "+o.Name+"="+o.Value+" */")
+		case "AUTO_RANDOM_BASE":
+			options = append(options, "/*T![auto_rand_base] "+o.Name+"="+o.Value+" */")
+		case "AUTO_ID_CACHE":
+			options = append(options, "/*T![auto_id_cache] "+o.Name+"="+o.Value+" */")
+		case "WITH SYSTEM VERSIONING":
+			options = append(options, o.Name)
+		case "WITHOUT ROWID", "STRICT":
+			sqliteOptions = append(sqliteOptions, o.Name)
+		default:
+			value := o.Value
+			if strings.ContainsAny(value, " '\"") {
+				value = String(value)
+			}
+			options = append(options, o.Name+"="+value)
+		}
+	}
+	if t.Comment != "" && !hasComment {
+		options = append(options, "COMMENT="+String(t.Comment))
+	}
+	if len(sqliteOptions) > 0 {
+		// mysql has no such options, they are kept as a comment
+		options = append(options, "/* "+strings.Join(sqliteOptions, ", ")+" */")
+	}
+	return strings.Join(options, " ")
+}
+
+// Quote quotes an identifier with backquotes.
+func Quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// QuoteList quotes and joins identifiers, e.g. `a`,`b`.
+func QuoteList(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, Quote(name))
+	}
+	return strings.Join(quoted, ",")
+}
+
+// String returns s as a sql string literal.
+func String(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `''`, "\n", `\n`, "\r", `\r`, "\x00", `\0`)
+	return "'" + replacer.Replace(s) + "'"
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/diff"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// diffSchemas writes the migration from an old schema to a new one,
+// and summarizes the changes of the models.
+func diffSchemas(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := flags.String("format", "goose", "migration format: goose or migrate (golang-migrate)")
+	outDir := flags.String("out-dir", "", "write the migration files into this directory instead of stdout")
+	name := flags.String("name", "schema", "name of the migration files")
+	version := flags.String("version", time.Now().UTC().Format("20060102150405"), "version of the migration files")
+	force := flags.Bool("force", false, "overwrite files which are not generated by sql-to-gorm")
+	jobs := flags.Int("j", 0, "number of statements parsed concurrently, 0 for the number of CPUs")
+	dialect := flags.String("dialect", convert.DialectMySQL, "dialect of the sql: "+strings.Join(mysqlDialects(), ", "))
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(),
+			"Usage: %s diff [flags] old new\n"+
+				"old and new are a file, a dir, a glob or -.\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *format != "goose" && *format != "migrate" {
+		fmt.Fprintf(os.Stderr, "unknown format %s\n", *format)
+		os.Exit(2)
+	}
+
+	// the migrations are mysql
+	if !convert.MySQLCompatible(*dialect) {
+		fmt.Fprintf(os.Stderr, "diff writes mysql migrations, not %s\n", *dialect)
+		os.Exit(2)
+	}
+
+	option := convert.DefaultOption()
+	option.Jobs = *jobs
+	option.Dialect = *dialect
+	schemas := make([]*schema.Schema, 0, 2)
+	for _, arg := range flags.Args() {
+		inputs, err := ExpandInputs([]string{arg})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(2)
+		}
+		schemas = append(schemas, parseInputs(inputs, "", nil, option))
+	}
+	up := diff.Migrate(schemas[0], schemas[1])
+	down := diff.Migrate(schemas[1], schemas[0])
+	models := diff.Models(schemas[0], schemas[1])
+	if len(up) == 0 && len(models) == 0 {
+		fmt.Fprintf(os.Stderr, "no changes\n")
+		return
+	}
+
+	// the sql goes to stdout, the summary to stderr,
+	// or the summary to stdout if the sql is written to files.
+	summary := io.Writer(os.Stdout)
+	var err error
+	switch {
+	case *outDir == "":
+		summary = os.Stderr
+		if *format == "goose" {
+			_, err = io.WriteString(os.Stdout, gooseMigration(up, down))
+		} else {
+			_, err = io.WriteString(os.Stdout, "-- up\n"+statements(up)+"\n-- down\n"+statements(down))
+		}
+	case *format == "goose":
+		path := filepath.Join(*outDir, *version+"_"+*name+".sql")
+		err = WriteFile(path, gooseMigration(up, down), *force)
+	default:
+		prefix := filepath.Join(*outDir, *version+"_"+*name)
+		if err = WriteFile(prefix+".up.sql", statements(up), *force); err == nil {
+			err = WriteFile(prefix+".down.sql", statements(down), *force)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "write fail: %s\n", err.Error())
+		os.Exit(1)
+	}
+	for _, m := range models {
+		fmt.Fprintln(summary, m.String())
+	}
+}
+
+func statements(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return strings.Join(list, "\n\n") + "\n"
+}
+
+// gooseMigration returns a goose sql migration, statements are wrapped
+// in StatementBegin and StatementEnd as they span lines.
+func gooseMigration(up, down []string) string {
+	buf := new(strings.Builder)
+	for _, section := range []struct {
+		name string
+		list []string
+	}{{"Up", up}, {"Down", down}} {
+		buf.WriteString("-- +goose " + section.name + "\n")
+		for _, stmt := range section.list {
+			buf.WriteString("-- +goose StatementBegin\n" + stmt + "\n-- +goose StatementEnd\n")
+		}
+		if section.name == "Up" {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
+
+// mysqlDialects are the dialects read by the MySQL grammar, those of diff.
+func mysqlDialects() []string {
+	result := make([]string, 0, len(convert.Dialects))
+	for _, d := range convert.Dialects {
+		if convert.MySQLCompatible(d) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
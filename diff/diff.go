@@ -0,0 +1,322 @@
+// Package diff compares two schemas, to write migrations between them
+// and to summarize how the models change.
+//
+// Tables, columns, indexes and constraints are matched by name, case-insensitively,
+// so a renamed column is a dropped column and an added one. Tables are matched
+// by their qualified name, see schema.Schema.Lookup.
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/ddl"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// Migrate returns the statements which migrate from to to, each ending with a semicolon.
+// Tables are created in the order of to and dropped in the reverse order of from.
+// The table names are qualified with their database if the schemas have tables
+// of several databases, e.g. of mysqldump --databases.
+func Migrate(from, to *schema.Schema) []string {
+	qualified := databases(from, to) > 1
+	name := func(t *schema.Table) string {
+		if !qualified || t.Database == "" {
+			return ddl.Quote(t.Name)
+		}
+		return ddl.Quote(t.Database) + "." + ddl.Quote(t.Name)
+	}
+	result := make([]string, 0)
+	for _, t := range to.Tables {
+		if from.Lookup(t.Database, t.Name) == nil {
+			create := strings.TrimPrefix(ddl.CreateTable(t), "CREATE TABLE "+ddl.Quote(t.Name))
+			result = append(result, "CREATE TABLE "+name(t)+create)
+		}
+	}
+	for _, t := range to.Tables {
+		if old := from.Lookup(t.Database, t.Name); old != nil {
+			result = append(result, alterTable(old, t, name(t))...)
+		}
+	}
+	for i := len(from.Tables) - 1; i >= 0; i-- {
+		if t := from.Tables[i]; to.Lookup(t.Database, t.Name) == nil {
+			result = append(result, fmt.Sprintf("DROP TABLE %s;", name(t)))
+		}
+	}
+	return result
+}
+
+// databases returns the number of databases of the tables of schemas.
+func databases(schemas ...*schema.Schema) int {
+	set := map[string]bool{}
+	for _, s := range schemas {
+		for _, t := range s.Tables {
+			set[strings.ToLower(t.Database)] = true
+		}
+	}
+	return len(set)
+}
+
+// AlterTable returns the ALTER TABLE statements which change from to to,
+// nil if they are the same. Foreign keys are dropped in a statement of their own,
+// since mysql can not drop and add a foreign key of the same name at once.
+func AlterTable(from, to *schema.Table) []string {
+	return alterTable(from, to, ddl.Quote(to.Name))
+}
+
+// alterTable is AlterTable of the table with the quoted name.
+func alterTable(from, to *schema.Table, name string) []string {
+	var drops, specs []string
+
+	// mariadb drops the versioning of a table before the columns of its period
+	_, fromVersioned := from.Option(systemVersioning)
+	_, toVersioned := to.Option(systemVersioning)
+	if fromVersioned && !toVersioned {
+		drops = append(drops, "DROP SYSTEM VERSIONING")
+	}
+
+	// drop changed or removed keys and constraints before the columns they use
+	fromConstraints := constraints(from)
+	toConstraints := constraints(to)
+	for _, c := range from.Constraints {
+		if other, ok := toConstraints[constraintKey(c)]; ok && ddl.Constraint(other) == ddl.Constraint(c) {
+			continue
+		}
+		switch {
+		case c.Name == "":
+			// an unnamed constraint has a name generated by mysql, it can not be dropped here
+		case c.Type == schema.ConstraintForeignKey:
+			drops = append(drops, "DROP FOREIGN KEY "+ddl.Quote(c.Name))
+		default:
+			specs = append(specs, "DROP CHECK "+ddl.Quote(c.Name))
+		}
+	}
+	fromIndexes := indexes(from)
+	toIndexes := indexes(to)
+	for _, idx := range from.Indexes {
+		if other, ok := toIndexes[indexKey(idx)]; ok && ddl.Index(other) == ddl.Index(idx) {
+			continue
+		}
+		if idx.Kind == schema.IndexPrimary {
+			specs = append(specs, "DROP PRIMARY KEY")
+		} else {
+			specs = append(specs, "DROP INDEX "+ddl.Quote(idx.Name))
+		}
+	}
+
+	for _, c := range from.Columns {
+		if to.Column(c.Name) == nil {
+			specs = append(specs, "DROP COLUMN "+ddl.Quote(c.Name))
+		}
+	}
+	for i, c := range to.Columns {
+		old := from.Column(c.Name)
+		switch {
+		case old == nil:
+			position := " FIRST"
+			if i > 0 {
+				position = " AFTER " + ddl.Quote(to.Columns[i-1].Name)
+			}
+			specs = append(specs, "ADD COLUMN "+ddl.Column(c)+position)
+		case ddl.Column(old) != ddl.Column(c):
+			specs = append(specs, "MODIFY COLUMN "+ddl.Column(c))
+		}
+	}
+
+	for _, idx := range to.Indexes {
+		if other, ok := fromIndexes[indexKey(idx)]; !ok || ddl.Index(other) != ddl.Index(idx) {
+			specs = append(specs, "ADD "+ddl.Index(idx))
+		}
+	}
+	for _, c := range to.Constraints {
+		if other, ok := fromConstraints[constraintKey(c)]; !ok || ddl.Constraint(other) != ddl.Constraint(c) {
+			specs = append(specs, "ADD "+ddl.Constraint(c))
+		}
+	}
+
+	if options := changedOptions(from, to); options != "" {
+		specs = append(specs, options)
+	}
+	if toVersioned && !fromVersioned {
+		specs = append(specs, "ADD SYSTEM VERSIONING")
+	}
+
+	result := make([]string, 0, 2)
+	for _, list := range [][]string{drops, specs} {
+		if len(list) > 0 {
+			result = append(result, fmt.Sprintf("ALTER TABLE %s\n  %s;",
+				name, strings.Join(list, ",\n  ")))
+		}
+	}
+	return result
+}
+
+// systemVersioning is the option of a mariadb table WITH SYSTEM VERSIONING,
+// it is added and dropped by alterTable.
+const systemVersioning = "WITH SYSTEM VERSIONING"
+
+// changedOptions returns the table options of to which differ from those of from.
+func changedOptions(from, to *schema.Table) string {
+	changed := &schema.Table{}
+	for _, o := range to.Options {
+		if o.Name == systemVersioning {
+			continue
+		}
+		if value, ok := from.Option(o.Name); !ok || value != o.Value {
+			changed.SetOption(o.Name, o.Value)
+		}
+	}
+	if from.Comment != to.Comment {
+		if _, ok := to.Option("COMMENT"); !ok {
+			changed.SetOption("COMMENT", to.Comment)
+		}
+	}
+	return ddl.TableOptions(changed)
+}
+
+func indexKey(idx *schema.Index) string {
+	if idx.Kind == schema.IndexPrimary {
+		return "PRIMARY"
+	}
+	return strings.ToLower(idx.Name)
+}
+
+// constraintKey is the lower case name, or the definition of an unnamed constraint.
+func constraintKey(c *schema.Constraint) string {
+	if c.Name == "" {
+		return ddl.Constraint(c)
+	}
+	return strings.ToLower(c.Name)
+}
+
+func indexes(t *schema.Table) map[string]*schema.Index {
+	result := map[string]*schema.Index{}
+	for _, idx := range t.Indexes {
+		result[indexKey(idx)] = idx
+	}
+	return result
+}
+
+func constraints(t *schema.Table) map[string]*schema.Constraint {
+	result := map[string]*schema.Constraint{}
+	for _, c := range t.Constraints {
+		result[constraintKey(c)] = c
+	}
+	return result
+}
+
+/////////////////////////////////////////////
+// models ///////////////////////////////////
+/////////////////////////////////////////////
+
+const (
+	Added   = "+"
+	Removed = "-"
+	Changed = "~"
+)
+
+// ModelChange is a changed model, the struct of a table.
+type ModelChange struct {
+	// Kind is Added, Removed or Changed.
+	Kind   string
+	Struct string
+	Table  string
+	Fields []FieldChange
+}
+
+// FieldChange is a changed field of a model.
+type FieldChange struct {
+	// Kind is Added, Removed or Changed.
+	Kind string
+	Name string
+	// Type is the go type, OldType is set if the type is changed.
+	Type    string
+	OldType string
+	// Tag is the gorm tag, OldTag is set if the tag is changed.
+	Tag    string
+	OldTag string
+}
+
+func (c ModelChange) String() string {
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, "%s %s (%s)", c.Kind, c.Struct, c.Table)
+	for _, f := range c.Fields {
+		buf.WriteString("\n    " + f.String())
+	}
+	return buf.String()
+}
+
+func (f FieldChange) String() string {
+	switch {
+	case f.Kind != Changed:
+		return fmt.Sprintf("%s %s %s", f.Kind, f.Name, f.Type)
+	case f.OldType != "":
+		return fmt.Sprintf("%s %s %s -> %s", f.Kind, f.Name, f.OldType, f.Type)
+	default:
+		return fmt.Sprintf("%s %s `gorm:\"%s\"` -> `gorm:\"%s\"`", f.Kind, f.Name, f.OldTag, f.Tag)
+	}
+}
+
+// Models returns how the models rendered from from change if rendered from to,
+// changes which the models do not show, e.g. of a foreign key, are not listed.
+func Models(from, to *schema.Schema) []ModelChange {
+	result := make([]ModelChange, 0)
+	for _, t := range to.Tables {
+		old := from.Lookup(t.Database, t.Name)
+		if old == nil {
+			result = append(result, ModelChange{Kind: Added, Struct: convert.GoName(t.Name), Table: t.Name})
+			continue
+		}
+		if fields := fieldChanges(old, t); len(fields) > 0 {
+			result = append(result, ModelChange{
+				Kind:   Changed,
+				Struct: convert.GoName(t.Name),
+				Table:  t.Name,
+				Fields: fields,
+			})
+		}
+	}
+	for _, t := range from.Tables {
+		if to.Lookup(t.Database, t.Name) == nil {
+			result = append(result, ModelChange{Kind: Removed, Struct: convert.GoName(t.Name), Table: t.Name})
+		}
+	}
+	return result
+}
+
+func fieldChanges(from, to *schema.Table) []FieldChange {
+	result := make([]FieldChange, 0)
+	for _, c := range from.Columns {
+		if to.Column(c.Name) == nil {
+			result = append(result, FieldChange{
+				Kind: Removed,
+				Name: convert.GoName(c.Name),
+				Type: convert.LookupType(c.Type).Name,
+			})
+		}
+	}
+	for _, c := range to.Columns {
+		f := FieldChange{
+			Name: convert.GoName(c.Name),
+			Type: convert.LookupType(c.Type).Name,
+		}
+		old := from.Column(c.Name)
+		if old == nil {
+			f.Kind = Added
+			result = append(result, f)
+			continue
+		}
+		if oldType := convert.LookupType(old.Type).Name; oldType != f.Type {
+			f.OldType = oldType
+		}
+		if oldTag, tag := convert.TableGormTag(from, old), convert.TableGormTag(to, c); oldTag != tag {
+			f.OldTag, f.Tag = oldTag, tag
+		}
+		if f.OldType != "" || f.OldTag != "" {
+			f.Kind = Changed
+			result = append(result, f)
+		}
+	}
+	return result
+}
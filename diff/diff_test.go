@@ -0,0 +1,134 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+func TestMigrate(t *testing.T) {
+	const users = "CREATE TABLE users (id bigint NOT NULL, name varchar(64) NOT NULL, PRIMARY KEY (id));"
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string
+		// dialect is that of from and to, mysql if empty
+		dialect string
+	}{{
+		name: "same",
+		from: users,
+		to:   users,
+	}, {
+		name: "add column",
+		from: users,
+		to:   "CREATE TABLE users (id bigint NOT NULL, age int, name varchar(64) NOT NULL, PRIMARY KEY (id));",
+		want: []string{"ALTER TABLE `users`\n  ADD COLUMN `age` int NULL AFTER `id`;"},
+	}, {
+		name: "drop column",
+		from: users,
+		to:   "CREATE TABLE users (id bigint NOT NULL, PRIMARY KEY (id));",
+		want: []string{"ALTER TABLE `users`\n  DROP COLUMN `name`;"},
+	}, {
+		name: "modify column",
+		from: users,
+		to:   "CREATE TABLE users (id bigint NOT NULL, name varchar(128) NOT NULL DEFAULT '', PRIMARY KEY (id));",
+		want: []string{"ALTER TABLE `users`\n  MODIFY COLUMN `name` varchar(128) NOT NULL DEFAULT '';"},
+	}, {
+		name: "indexes",
+		from: "CREATE TABLE users (id bigint NOT NULL, name varchar(64), age int, KEY idx_name (name), KEY idx_age (age));",
+		to:   "CREATE TABLE users (id bigint NOT NULL, name varchar(64), age int, PRIMARY KEY (id), KEY idx_name (name, age), UNIQUE KEY uk_age (age));",
+		want: []string{"ALTER TABLE `users`\n" +
+			"  DROP INDEX `idx_name`,\n" +
+			"  DROP INDEX `idx_age`,\n" +
+			"  ADD PRIMARY KEY (`id`),\n" +
+			"  ADD KEY `idx_name` (`name`,`age`),\n" +
+			"  ADD UNIQUE KEY `uk_age` (`age`);"},
+	}, {
+		name: "foreign keys",
+		from: users + "CREATE TABLE orders (id bigint NOT NULL, user_id bigint, " +
+			"CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));",
+		to: users + "CREATE TABLE orders (id bigint NOT NULL, user_id bigint, " +
+			"CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE, " +
+			"CONSTRAINT chk_id CHECK (id > 0));",
+		want: []string{
+			"ALTER TABLE `orders`\n  DROP FOREIGN KEY `fk_user`;",
+			"ALTER TABLE `orders`\n" +
+				"  ADD CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,\n" +
+				"  ADD CONSTRAINT `chk_id` CHECK (id > 0);",
+		},
+	}, {
+		name: "drop foreign key",
+		from: users + "CREATE TABLE orders (id bigint NOT NULL, user_id bigint, " +
+			"CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));",
+		to:   users + "CREATE TABLE orders (id bigint NOT NULL, user_id bigint);",
+		want: []string{"ALTER TABLE `orders`\n  DROP FOREIGN KEY `fk_user`;"},
+	}, {
+		name: "rename table",
+		from: users,
+		to:   "CREATE TABLE members (id bigint NOT NULL, name varchar(64) NOT NULL, PRIMARY KEY (id));",
+		want: []string{
+			"CREATE TABLE `members` (\n  `id` bigint NOT NULL,\n  `name` varchar(64) NOT NULL,\n  PRIMARY KEY (`id`)\n);",
+			"DROP TABLE `users`;",
+		},
+	}, {
+		name: "databases",
+		from: "USE shop;" + users + "USE audit;" + users,
+		to:   "USE shop;" + users + "USE audit;CREATE TABLE users (id bigint NOT NULL, PRIMARY KEY (id));",
+		want: []string{"ALTER TABLE `audit`.`users`\n  DROP COLUMN `name`;"},
+	}, {
+		name: "drop table of a database",
+		from: "USE shop;" + users + "USE audit;" + users,
+		to:   "USE shop;" + users,
+		want: []string{"DROP TABLE `audit`.`users`;"},
+	}, {
+		name:    "add system versioning",
+		from:    users,
+		to:      "CREATE TABLE users (id bigint NOT NULL, name varchar(64) NOT NULL, PRIMARY KEY (id)) WITH SYSTEM VERSIONING;",
+		want:    []string{"ALTER TABLE `users`\n  ADD SYSTEM VERSIONING;"},
+		dialect: convert.DialectMariaDB,
+	}, {
+		name:    "drop system versioning",
+		from:    "CREATE TABLE users (id bigint NOT NULL, name varchar(64) NOT NULL, PRIMARY KEY (id)) WITH SYSTEM VERSIONING;",
+		to:      "CREATE TABLE users (id bigint NOT NULL, PRIMARY KEY (id));",
+		want:    []string{"ALTER TABLE `users`\n  DROP SYSTEM VERSIONING;", "ALTER TABLE `users`\n  DROP COLUMN `name`;"},
+		dialect: convert.DialectMariaDB,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Migrate(parseDialect(t, tt.from, tt.dialect), parseDialect(t, tt.to, tt.dialect))
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Migrate =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestModels(t *testing.T) {
+	from := parse(t, "USE shop; CREATE TABLE users (id bigint, name varchar(64));"+
+		"USE audit; CREATE TABLE users (id bigint, at datetime);")
+	to := parse(t, "USE shop; CREATE TABLE users (id bigint, name varchar(64));"+
+		"USE audit; CREATE TABLE users (id bigint, at timestamp);")
+	got := Models(from, to)
+	want := "~ Users (users)\n    ~ At time.Time -> int64"
+	if len(got) != 1 || got[0].String() != want {
+		t.Errorf("Models = %v, want %s", got, want)
+	}
+}
+
+func parse(t *testing.T, sql string) *schema.Schema {
+	t.Helper()
+	return parseDialect(t, sql, "")
+}
+
+func parseDialect(t *testing.T, sql, dialect string) *schema.Schema {
+	t.Helper()
+	option := convert.DefaultOption()
+	option.Dialect = dialect
+	s, err := convert.Convert(strings.NewReader(sql), option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
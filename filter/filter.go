@@ -0,0 +1,162 @@
+// Package filter selects tables by name, e.g. -tables 'user_*,/^order_\d+$/'.
+//
+// A pattern is a glob, or a regular expression between slashes.
+// A glob with a dot matches the schema-qualified name, e.g. shop.*,
+// others match the table name; a regular expression matches either.
+// Names are matched case-insensitively.
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// Filter matches tables against include and exclude patterns.
+// A nil Filter matches every table.
+type Filter struct {
+	include []*pattern
+	exclude []*pattern
+}
+
+// Config is the config file equivalent of the -tables and -exclude flags.
+type Config struct {
+	Tables  []string `json:"tables"`
+	Exclude []string `json:"exclude"`
+}
+
+// LoadConfig reads a json config file like {"tables": ["user_*"], "exclude": ["*_bak"]}.
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := json.Unmarshal(content, c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// New returns a filter of include and exclude patterns, nil if there are none.
+func New(include, exclude []string) (*Filter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	f := &Filter{}
+	var err error
+	if f.include, err = compile(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compile(exclude); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Match reports whether the table database.name matches an include pattern,
+// or there are none, and matches no exclude pattern. database may be empty.
+func (f *Filter) Match(database, name string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.include) > 0 && !matchAny(f.include, database, name) {
+		return false
+	}
+	return !matchAny(f.exclude, database, name)
+}
+
+// Schema returns the tables of s matching f, s itself if f is nil.
+func (f *Filter) Schema(s *schema.Schema) *schema.Schema {
+	if f == nil {
+		return s
+	}
+	result := &schema.Schema{}
+	for _, t := range s.Tables {
+		if f.Match(t.Database, t.Name) {
+			result.Tables = append(result.Tables, t)
+		}
+	}
+	return result
+}
+
+type pattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+func (p *pattern) match(database, name string) bool {
+	qualified := name
+	if database != "" {
+		qualified = database + "." + name
+	}
+	if p.re != nil {
+		return p.re.MatchString(name) || p.re.MatchString(qualified)
+	}
+	if strings.Contains(p.glob, ".") {
+		name = qualified
+	}
+	ok, _ := path.Match(p.glob, strings.ToLower(name))
+	return ok
+}
+
+func compile(patterns []string) ([]*pattern, error) {
+	result := make([]*pattern, 0, len(patterns))
+	for _, p := range patterns {
+		if len(p) > 1 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+			re, err := regexp.Compile("(?i)" + p[1:len(p)-1])
+			if err != nil {
+				return nil, fmt.Errorf("pattern %s: %w", p, err)
+			}
+			result = append(result, &pattern{re: re})
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("pattern %s: %w", p, err)
+		}
+		result = append(result, &pattern{glob: strings.ToLower(p)})
+	}
+	return result, nil
+}
+
+func matchAny(patterns []*pattern, database, name string) bool {
+	for _, p := range patterns {
+		if p.match(database, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Split splits comma separated patterns, a comma in a regular expression
+// between slashes does not split, e.g. /^a{1,2}$/,b.
+func Split(patterns string) []string {
+	result := make([]string, 0)
+	for len(patterns) > 0 {
+		end := strings.IndexByte(patterns, ',')
+		if strings.HasPrefix(patterns, "/") {
+			// the comma after the closing slash
+			if i := strings.Index(patterns[1:], "/,"); i >= 0 {
+				end = i + 2
+			} else {
+				end = -1
+			}
+		}
+		if end < 0 {
+			end = len(patterns)
+		}
+		if p := strings.TrimSpace(patterns[:end]); p != "" {
+			result = append(result, p)
+		}
+		if end == len(patterns) {
+			break
+		}
+		patterns = patterns[end+1:]
+	}
+	return result
+}
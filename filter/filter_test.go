@@ -0,0 +1,95 @@
+package filter_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/filter"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		include, exclude []string
+		database, name   string
+		want             bool
+	}{
+		{nil, nil, "", "users", true},
+		{[]string{"user*"}, nil, "shop", "users", true},
+		{[]string{"user*"}, nil, "shop", "orders", false},
+		{[]string{"shop.*"}, nil, "shop", "orders", true},
+		{[]string{"shop.*"}, nil, "", "orders", false},
+		{[]string{"SHOP.Order*"}, nil, "shop", "orders", true},
+		{[]string{"shop.*"}, []string{"*.orders"}, "shop", "orders", false},
+		{[]string{"/^shop\\.ord/"}, nil, "shop", "orders", true},
+		{[]string{"/^ord/"}, nil, "shop", "orders", true},
+		{nil, []string{"*_bak"}, "shop", "orders_bak", false},
+	}
+	for _, tt := range tests {
+		f, err := filter.New(tt.include, tt.exclude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := f.Match(tt.database, tt.name); got != tt.want {
+			t.Errorf("%v -%v Match(%q, %q) = %v, want %v", tt.include, tt.exclude, tt.database, tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestSchema filters the tables of a file and of the SHOW CREATE TABLE output of a database,
+// which are both in the database shop.
+func TestSchema(t *testing.T) {
+	dsn := map[string]string{
+		"orders":     "CREATE TABLE `orders` (`id` bigint NOT NULL)",
+		"order_bak":  "CREATE TABLE `order_bak` (`id` bigint NOT NULL)",
+		"order_item": "CREATE TABLE `order_item` (`id` bigint NOT NULL)",
+	}
+	tests := []struct {
+		name  string
+		input func(c *convert.Converter) error
+	}{{
+		name: "file",
+		input: func(c *convert.Converter) error {
+			sql := "USE shop;\n"
+			for _, name := range []string{"orders", "order_bak", "order_item"} {
+				sql += dsn[name] + ";\n"
+			}
+			return c.Add("shop.sql", strings.NewReader(sql))
+		},
+	}, {
+		name: "dsn",
+		input: func(c *convert.Converter) error {
+			for _, name := range []string{"orders", "order_bak", "order_item"} {
+				if err := c.AddDatabase("shop", "shop."+name, strings.NewReader(dsn[name])); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := convert.NewConverter(convert.DefaultOption())
+			if err := tt.input(c); err != nil {
+				t.Fatal(err)
+			}
+			f, err := filter.New([]string{"shop.order*"}, []string{"shop.*_bak"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := names(f.Schema(c.Schema())), []string{"shop.orders", "shop.order_item"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("Schema = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func names(s *schema.Schema) []string {
+	result := make([]string, 0, len(s.Tables))
+	for _, t := range s.Tables {
+		result = append(result, t.QualifiedName())
+	}
+	return result
+}
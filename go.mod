@@ -1,5 +1,17 @@
 module github.com/er1c-zh/sql-to-gorm
 
-go 1.16
+// x/tools, which struct-to-sql loads the models with, requires go 1.25 in the releases
+// working with current toolchains.
+go 1.25.0
 
-require github.com/antlr/antlr4 v0.0.0-20210427155808-62554204404f // indirect
+require (
+	github.com/antlr/antlr4 v0.0.0-20210427155808-62554204404f
+	github.com/go-sql-driver/mysql v1.8.1
+	golang.org/x/tools v0.47.0
+)
+
+require (
+	filippo.io/edwards25519 v1.1.0 // indirect
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+)
@@ -0,0 +1,77 @@
+// Package introspect reads the tables of a live mysql database
+// with SHOW CREATE TABLE, to be parsed like a schema file.
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/er1c-zh/sql-to-gorm/filter"
+)
+
+// Table is the CREATE TABLE statement of a table.
+type Table struct {
+	Name   string
+	Create string
+}
+
+// Open connects to the database of dsn, e.g. user:pass@tcp(localhost:3306)/db,
+// and returns the name of the database.
+func Open(dsn string) (*sql.DB, string, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.DBName == "" {
+		return nil, "", fmt.Errorf("no database in dsn")
+	}
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return sql.OpenDB(connector), cfg.DBName, nil
+}
+
+// TableNames returns the base tables of the current database in order, views are left out.
+func TableNames(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SHOW FULL TABLES WHERE Table_type = 'BASE TABLE'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]string, 0)
+	for rows.Next() {
+		var name, tableType string
+		if err := rows.Scan(&name, &tableType); err != nil {
+			return nil, err
+		}
+		result = append(result, name)
+	}
+	return result, rows.Err()
+}
+
+// Tables returns the CREATE TABLE statements of the tables of database matching f,
+// database is the current database of db.
+func Tables(ctx context.Context, db *sql.DB, database string, f *filter.Filter) ([]*Table, error) {
+	names, err := TableNames(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Table, 0, len(names))
+	for _, name := range names {
+		if !f.Match(database, name) {
+			continue
+		}
+		t := &Table{}
+		row := db.QueryRowContext(ctx, "SHOW CREATE TABLE `"+strings.ReplaceAll(name, "`", "``")+"`")
+		if err := row.Scan(&t.Name, &t.Create); err != nil {
+			return nil, fmt.Errorf("show create table %s: %w", name, err)
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/filter"
+)
+
+// server is an in-process stand-in for mysql, it answers the statements of this package.
+type server struct {
+	// tables are the names and types of the tables, in order
+	tables [][2]string
+	// queries are the statements received
+	queries []string
+}
+
+func (s *server) Connect(context.Context) (driver.Conn, error) { return &conn{s}, nil }
+func (s *server) Driver() driver.Driver                        { return nil }
+
+type conn struct{ s *server }
+
+func (c *conn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *conn) Close() error                        { return nil }
+func (c *conn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (c *conn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.s.queries = append(c.s.queries, query)
+	r := &rows{}
+	switch {
+	case query == "SHOW FULL TABLES WHERE Table_type = 'BASE TABLE'":
+		r.columns = []string{"Tables_in_shop", "Table_type"}
+		for _, t := range c.s.tables {
+			if t[1] == "BASE TABLE" {
+				r.values = append(r.values, []driver.Value{t[0], t[1]})
+			}
+		}
+	case strings.HasPrefix(query, "SHOW CREATE TABLE "):
+		name := strings.TrimPrefix(query, "SHOW CREATE TABLE ")
+		name = strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+		r.columns = []string{"Table", "Create Table"}
+		for _, t := range c.s.tables {
+			if t[0] == name {
+				r.values = append(r.values, []driver.Value{name, fmt.Sprintf("CREATE TABLE `%s` (`id` int)", name)})
+			}
+		}
+		if len(r.values) == 0 {
+			return nil, fmt.Errorf("Table 'shop.%s' doesn't exist", name)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected query %s", query)
+	}
+	return r, nil
+}
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func open(tables ...[2]string) (*sql.DB, *server) {
+	s := &server{tables: tables}
+	return sql.OpenDB(s), s
+}
+
+func TestTableNames(t *testing.T) {
+	db, _ := open(
+		[2]string{"users", "BASE TABLE"},
+		[2]string{"active_users", "VIEW"},
+		[2]string{"orders", "BASE TABLE"},
+	)
+	defer db.Close()
+	names, err := TableNames(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"users", "orders"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("TableNames = %v, want %v", names, want)
+	}
+}
+
+func TestTables(t *testing.T) {
+	tables := [][2]string{
+		{"users", "BASE TABLE"},
+		{"user_logs", "BASE TABLE"},
+		{"user_logs_bak", "BASE TABLE"},
+		{"user_view", "VIEW"},
+		{"odd`name", "BASE TABLE"},
+	}
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             []string
+	}{
+		{name: "all", want: []string{"users", "user_logs", "user_logs_bak", "odd`name"}},
+		{name: "glob", include: []string{"user*"}, exclude: []string{"*_bak"}, want: []string{"users", "user_logs"}},
+		{name: "qualified glob", include: []string{"shop.user_*"}, want: []string{"user_logs", "user_logs_bak"}},
+		{name: "other database", include: []string{"audit.*"}, want: []string{}},
+		{name: "regexp", include: []string{"/^odd/"}, want: []string{"odd`name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, s := open(tables...)
+			defer db.Close()
+			f, err := filter.New(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := Tables(context.Background(), db, "shop", f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, 0, len(result))
+			for _, table := range result {
+				names = append(names, table.Name)
+				if want := fmt.Sprintf("CREATE TABLE `%s` (`id` int)", table.Name); table.Create != want {
+					t.Errorf("Create = %s, want %s", table.Create, want)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Tables = %v, want %v", names, tt.want)
+			}
+			// the tables which do not match are not read
+			if got := len(s.queries); got != len(tt.want)+1 {
+				t.Errorf("sent %d queries, want %d: %v", got, len(tt.want)+1, s.queries)
+			}
+		})
+	}
+}
+
+func TestTablesError(t *testing.T) {
+	db, _ := open([2]string{"users", "BASE TABLE"})
+	db.Close()
+	if _, err := Tables(context.Background(), db, "shop", nil); err == nil {
+		t.Errorf("Tables of a closed db succeeded")
+	}
+}
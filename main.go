@@ -2,336 +2,226 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
-	res "github.com/antlr/antlr4/doc/resources"
-	"github.com/antlr/antlr4/runtime/Go/antlr"
-	gen "github.com/er1c-zh/sql-to-gorm/antlr4_gen"
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/filter"
+	"github.com/er1c-zh/sql-to-gorm/introspect"
+	"github.com/er1c-zh/sql-to-gorm/schema"
 )
 
 var (
 	path     string
 	_package string
+	out      string
+	outDir   string
+	force    bool
+	mergeOut bool
+	dsn      string
+	tables   string
+	exclude  string
+	config   string
+	format   string
+	tmplPath string
+	jobs     int
+	kinds    string
+	dialect  string
 )
 
 func Init() {
-	flag.StringVar(&path, "file", "", "path to sql file")
+	flag.StringVar(&path, "file", "", "path to sql file, same as a positional argument")
 	flag.StringVar(&_package, "package", "models", "go file package")
-	flag.Parse()
-}
-
-func main() {
-	Init()
-
-	input, err := antlr.NewFileStream(path)
-	if err != nil {
-		fmt.Printf("NewFileStream fail: %s", err.Error())
-		flag.Usage()
-		return
+	flag.StringVar(&out, "out", "", "write all models to this file instead of stdout")
+	flag.StringVar(&outDir, "out-dir", "", "write one file per table into this directory")
+	flag.BoolVar(&force, "force", false, "overwrite files which are not generated by sql-to-gorm")
+	flag.BoolVar(&mergeOut, "merge", false, "update only the generated fields of existing files, keeping code written by hand")
+	flag.StringVar(&format, "format", "gorm", "output format: gorm or json, json dumps the parsed schema")
+	flag.StringVar(&tmplPath, "template", "", "path or glob of text/template files to render models with")
+	flag.StringVar(&dsn, "dsn", "", "read the tables of a live mysql database, e.g. user:pass@tcp(localhost:3306)/db")
+	flag.StringVar(&tables, "tables", "", "comma separated patterns of the tables to generate, globs or /regexp/, e.g. user_*,shop.*")
+	flag.StringVar(&exclude, "exclude", "", "comma separated patterns of the tables not to generate")
+	flag.StringVar(&config, "config", "", "json config file with the tables and exclude patterns")
+	flag.IntVar(&jobs, "j", 0, "number of statements parsed concurrently, 0 for the number of CPUs")
+	flag.StringVar(&kinds, "statements", strings.Join(convert.DefaultStatements, ","),
+		"comma separated kinds of statements to parse, others are skipped")
+	flag.StringVar(&dialect, "dialect", convert.DialectMySQL,
+		"dialect of the sql: "+strings.Join(convert.Dialects, ", "))
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(),
+			"Usage: %s [flags] [file|dir|glob|-]...\n"+
+				"       %s struct-to-sql [flags] [package]...\n"+
+				"       %s check [flags] [file|dir|glob|-]...\n"+
+				"       %s diff [flags] old new\n", os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+		flag.PrintDefaults()
 	}
-
-	lexer := gen.NewMySqlLexer(res.NewCaseChangingStream(input, true))
-	stream := antlr.NewCommonTokenStream(lexer, 0)
-	p := gen.NewMySqlParser(stream)
-	// p.AddErrorListener(antlr.NewDiagnosticErrorListener(true))
-	p.BuildParseTrees = true
-
-	option := DefaultOption()
-	option.Package = _package
-	ln := NewListener(option)
-	antlr.ParseTreeWalkerDefault.Walk(ln, p.Root())
-
-	fmt.Printf("%s", ln.ToGorm())
+	flag.Parse()
 }
 
-// GoModelFile model file
-type GoModelFile struct {
-	TableList []*Table
-	Import    map[string]interface{}
-	Package   string
+// commands are the subcommands, run as the first argument.
+var commands = map[string]func(args []string){
+	"struct-to-sql": structToSQL,
+	"check":         checkModels,
+	"diff":          diffSchemas,
 }
 
-func (f GoModelFile) ToGorm() string {
-	buf := new(bytes.Buffer)
-	pkg := f.Package
-	if pkg == "" {
-		pkg = "models"
-	}
-	buf.WriteString(fmt.Sprintf("package %s\n", pkg))
-
-	if len(f.Import) > 0 {
-		buf.WriteString("import (\n")
-		for _import := range f.Import {
-			buf.WriteString(fmt.Sprintf("    \"%s\"\n", _import))
+func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			cmd(os.Args[2:])
+			return
 		}
-		buf.WriteString(")\n")
-	}
-
-	for _, t := range f.TableList {
-		buf.WriteString(t.ToGorm())
-		buf.WriteString("\n")
-	}
-
-	return buf.String()
-}
-
-type Table struct {
-	Name string
-	Cols []*Col
-}
-
-func (t Table) ToGorm() string {
-	buf := new(bytes.Buffer)
-	buf.WriteByte('\n')
-	buf.WriteString(fmt.Sprintf("type %s struct {\n", snakeToCamel(t.Name)))
-	cols := make([]string, 0, len(t.Cols))
-	for _, col := range t.Cols {
-		cols = append(cols, col.ToGorm())
 	}
-	buf.WriteString(strings.Join(cols, "\n"))
-	buf.WriteString("\n}\n")
-	return buf.String()
-}
-
-type Col struct {
-	Name     string
-	DataType string
-	NotNull  bool
-	Default  string
-	Comment  string
-}
+	Init()
 
-func (c Col) ToGorm() string {
-	comment := c.Name
-	if c.Comment != "" {
-		comment = c.Comment
-	}
-	tagList := make([]string, 0, 1)
-	tagList = append(tagList, fmt.Sprintf("column:%s", c.Name))
-	if c.Default != "" {
-		tagList = append(tagList, fmt.Sprintf("default:%s", c.Default))
+	args := flag.Args()
+	if path != "" {
+		args = append([]string{path}, args...)
 	}
-
-	return fmt.Sprintf("    %s %s `gorm:\"%s\"` //%s",
-		snakeToCamel(c.Name), c.DataType, strings.Join(tagList, ";"), comment)
-}
-
-type Listener struct {
-	*gen.BaseMySqlParserListener
-	CurrentTable *Table
-	CurrentCol   *Col
-
-	GoModelFile
-}
-
-type Option struct {
-	Package string
-}
-
-func DefaultOption() Option {
-	return Option{
-		Package: "models",
+	if len(args) == 0 && dsn == "" {
+		flag.Usage()
+		os.Exit(2)
 	}
-}
-
-func NewListener(option Option) *Listener {
-	ln := &Listener{
-		GoModelFile: GoModelFile{
-			Import: map[string]interface{}{},
-		},
+	if dsn != "" && !convert.MySQLCompatible(dialect) {
+		fmt.Fprintf(os.Stderr, "-dsn reads mysql compatible databases, not %s\n", dialect)
+		os.Exit(2)
 	}
-	ln.Package = option.Package
-	return ln
-}
-
-func (l *Listener) EnterColumnCreateTable(ctx *gen.ColumnCreateTableContext) {
-	if l.CurrentTable != nil {
-		panic("last table not done")
+	inputs, err := ExpandInputs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(2)
 	}
 
-	tableNameList := strings.Split(
-		strings.Trim(ctx.TableName().GetText(), "`"), ".")
-
-	l.CurrentTable = &Table{
-		Name: tableNameList[len(tableNameList)-1],
+	option := convert.DefaultOption()
+	option.Package = _package
+	option.Template = tmplPath
+	option.Jobs = jobs
+	option.Statements = strings.Split(kinds, ",")
+	option.Dialect = dialect
+	f, err := newFilter(config, tables, exclude)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(2)
+	}
+	parsed := parseInputs(inputs, dsn, f, option)
+
+	var render func(w io.Writer, s *schema.Schema) error
+	switch format {
+	case "gorm":
+		tmpl, err := convert.ParseTemplate(tmplPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "parse template fail: %s\n", err.Error())
+			os.Exit(2)
+		}
+		// the models of all tables have unique names, also if written into a file each
+		option.StructNames = convert.StructNames(parsed)
+		render = func(w io.Writer, s *schema.Schema) error {
+			return convert.RenderTemplate(s, w, tmpl, option)
+		}
+	case "json":
+		render = func(w io.Writer, s *schema.Schema) error {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(s)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %s\n", format)
+		os.Exit(2)
 	}
-}
-func (l *Listener) ExitColumnCreateTable(ctx *gen.ColumnCreateTableContext) {
-	l.TableList = append(l.TableList, l.CurrentTable)
-	l.CurrentTable = nil
-}
 
-func (l *Listener) EnterColumnDeclaration(ctx *gen.ColumnDeclarationContext) {
-	if l.CurrentCol != nil {
-		panic("last col not done")
+	write := func(path, content string) error {
+		return WriteFile(path, content, force)
 	}
-	l.CurrentCol = &Col{
-		Name:    strings.Trim(ctx.Uid().GetText(), "`"),
-		NotNull: false,
-		Default: "",
+	if mergeOut {
+		if format != "gorm" {
+			fmt.Fprintf(os.Stderr, "-merge only works with -format gorm\n")
+			os.Exit(2)
+		}
+		write = MergeFile
+	}
+	switch {
+	case outDir != "":
+		err = WriteDir(outDir, parsed, render, write)
+	case out != "":
+		buf := new(bytes.Buffer)
+		if err = render(buf, parsed); err == nil {
+			err = write(out, buf.String())
+		}
+	default:
+		err = render(os.Stdout, parsed)
 	}
-}
-func (l *Listener) ExitColumnDeclaration(ctx *gen.ColumnDeclarationContext) {
-	if l.CurrentTable == nil {
-		panic("col done but no table")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "write fail: %s\n", err.Error())
+		os.Exit(1)
 	}
-	l.CurrentTable.Cols = append(l.CurrentTable.Cols, l.CurrentCol)
-	l.CurrentCol = nil
 }
 
-/////////////////////////////////////////////
-// string ///////////////////////////////////
-/////////////////////////////////////////////
-func (l *Listener) EnterStringDataType(c *gen.StringDataTypeContext) {
-	l.ParseDataType(c.GetTypeName().GetText(), []Rule{
-		{contain: "", _type: "string"},
-	})
-}
-func (l *Listener) EnterNationalStringDataType(c *gen.NationalStringDataTypeContext) {
-	l.ParseDataType(c.GetTypeName().GetText(), []Rule{
-		{contain: "", _type: "string"},
-	})
-}
-func (l *Listener) EnterNationalVaryingStringDataType(c *gen.NationalVaryingStringDataTypeContext) {
-	l.ParseDataType(c.GetTypeName().GetText(), []Rule{
-		{contain: "", _type: "string"},
-	})
-}
-
-/////////////////////////////////////////////
-// Dimension ////////////////////////////////
-/////////////////////////////////////////////
-
-func (l *Listener) EnterDimensionDataType(c *gen.DimensionDataTypeContext) {
-	l.ParseDataType(c.GetTypeName().GetText(), []Rule{
-		{contain: "int", _type: "int64"},
-		{contain: "timestamp", _type: "int64"},
-		{contain: "datetime", _type: "time.Time{}", repo: []string{"time"}},
-		{contain: "year", _type: "time.Time{}", repo: []string{"time"}},
-		{contain: "", _type: "float64"},
-	})
-}
-
-/////////////////////////////////////////////
-// simple data type//////////////////////////
-/////////////////////////////////////////////
-
-func (l *Listener) EnterSimpleDataType(c *gen.SimpleDataTypeContext) {
-	l.ParseDataType(c.GetTypeName().GetText(), []Rule{
-		{contain: "date", _type: "time.Time{}", repo: []string{"time"}},
-		{contain: "bool", _type: "bool"},
-		{contain: "serial", _type: "int64"},
-		{contain: "", _type: "string"},
-	})
-}
-
-/////////////////////////////////////////////
-// collection data type//////////////////////
-/////////////////////////////////////////////
-
-func (l *Listener) EnterCollectionDataType(c *gen.CollectionDataTypeContext) {
-	l.ParseDataType(c.GetTypeName().GetText(), []Rule{
-		{contain: "", _type: "string"},
-	})
-}
-
-/////////////////////////////////////////////
-// spatial data type/////////////////////////
-/////////////////////////////////////////////
-func (l *Listener) EnterSpatialDataType(c *gen.SpatialDataTypeContext) {
-	l.ParseDataType(c.GetTypeName().GetText(), []Rule{
-		{contain: "", _type: "string"},
-	})
-}
-
-/////////////////////////////////////////////
-// long varchar data type////////////////////
-/////////////////////////////////////////////
-
-func (l *Listener) EnterLongVarcharDataType(c *gen.LongVarcharDataTypeContext) {
-	l.ParseDataType(c.GetTypeName().GetText(), []Rule{
-		{contain: "", _type: "string"},
-	})
-}
-
-/////////////////////////////////////////////
-// long varbinary data type//////////////////
-/////////////////////////////////////////////
-func (l *Listener) EnterLongVarbinaryDataType(c *gen.LongVarbinaryDataTypeContext) {
-	l.ParseDataType(c.GetText(), []Rule{
-		{contain: "", _type: "string"},
-	})
-}
-
-/////////////////////////////////////////////
-// comment //////////////////////////////////
-/////////////////////////////////////////////
-func (l *Listener) EnterCommentColumnConstraint(c *gen.CommentColumnConstraintContext) {
-	if l.CurrentCol == nil {
-		return
+// parseInputs parses the sql inputs, then the tables of dsn if it is set,
+// into one schema and returns the tables matching f, exits on errors.
+func parseInputs(inputs []string, dsn string, f *filter.Filter, option convert.Option) *schema.Schema {
+	converter := convert.NewConverter(option)
+	var errList convert.ErrorList
+	for _, input := range inputs {
+		r, err := OpenInput(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read %s fail: %s\n", input, err.Error())
+			os.Exit(1)
+		}
+		err = converter.Add(input, r)
+		r.Close()
+		if err != nil {
+			errList = append(errList, err)
+		}
 	}
-	l.CurrentCol.Comment = c.STRING_LITERAL().GetText()
-}
-
-/////////////////////////////////////////////
-// default //////////////////////////////////
-/////////////////////////////////////////////
-func (l *Listener) EnterDefaultColumnConstraint(c *gen.DefaultColumnConstraintContext) {
-	if l.CurrentCol == nil {
-		return
+	if dsn != "" {
+		tables, database, err := readDSN(dsn, f)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read database fail: %s\n", err.Error())
+			os.Exit(1)
+		}
+		// the source of a table is database.table, the dsn may hold a password;
+		// the tables are in database, like those of a dump, for the filter and diff
+		for _, t := range tables {
+			if err := converter.AddDatabase(database, database+"."+t.Name, strings.NewReader(t.Create)); err != nil {
+				errList = append(errList, err)
+			}
+		}
 	}
-	l.CurrentCol.Default = c.DefaultValue().GetText()
-}
-
-/////////////////////////////////////////////
-/////////////////////////////////////////////
-/////////////////////////////////////////////
-
-func (l *Listener) SetDataType(_t string) {
-	if l.CurrentCol == nil {
-		fmt.Printf("[WARN] get data type but no col: %s", _t)
-		return
+	if len(errList) > 0 {
+		fmt.Fprintf(os.Stderr, "%s\n", errList.Error())
+		os.Exit(1)
 	}
-	l.CurrentCol.DataType = _t
-}
 
-type Rule struct {
-	contain string
-	_type   string
-	repo    []string
+	// foreign keys may reference tables which are filtered out
+	parsed := converter.Schema()
+	kept := f.Schema(parsed)
+	convert.CheckReferencesIn(kept, parsed, option)
+	return kept
 }
 
-func (l *Listener) ParseDataType(_t string, rule []Rule) {
-	typeName := strings.ToLower(_t)
-	f := func(src string, contain string, _type string, repo []string) bool {
-		if strings.Contains(src, contain) {
-			l.SetDataType(_type)
-			if len(repo) > 0 {
-				for _, _import := range repo {
-					l.Import[_import] = struct{}{}
-				}
-			}
-			return true
-		}
-		return false
-	}
-	for _, item := range rule {
-		if f(typeName, item.contain, item._type, item.repo) {
-			break
+// newFilter returns the filter of the config file, if any, and the flags.
+func newFilter(config, tables, exclude string) (*filter.Filter, error) {
+	c := &filter.Config{}
+	if config != "" {
+		var err error
+		if c, err = filter.LoadConfig(config); err != nil {
+			return nil, err
 		}
 	}
+	return filter.New(append(c.Tables, filter.Split(tables)...), append(c.Exclude, filter.Split(exclude)...))
 }
 
-func snakeToCamel(src string) string {
-	l := strings.Split(src, "_")
-	for i := 0; i < len(l); i++ {
-		if len(l[i]) == 0 {
-			continue
-		}
-		leading := strings.ToUpper(l[i][0:1])
-		l[i] = leading + l[i][1:]
+func readDSN(dsn string, f *filter.Filter) ([]*introspect.Table, string, error) {
+	db, database, err := introspect.Open(dsn)
+	if err != nil {
+		return nil, "", err
 	}
-	return strings.Join(l, "")
+	defer db.Close()
+	tables, err := introspect.Tables(context.Background(), db, database, f)
+	return tables, database, err
 }
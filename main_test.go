@@ -0,0 +1,164 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+func TestWriteDir(t *testing.T) {
+	s := &schema.Schema{Tables: []*schema.Table{
+		{Name: "user_info"},
+		{Name: "users", Database: "shop"},
+		{Name: "users", Database: "audit"},
+		{Name: "Users", Database: "shop"},
+		{Name: "../escaped"},
+		{Name: "a/b"},
+		{Name: ".."},
+		{Name: "order_test"},
+	}}
+	render := func(w io.Writer, s *schema.Schema) error {
+		_, err := io.WriteString(w, s.Tables[0].Name)
+		return err
+	}
+	written := map[string]string{}
+	write := func(path, content string) error {
+		written[path] = content
+		return nil
+	}
+	if err := WriteDir("models", s, render, write); err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]string{
+		"user_info.go":        "user_info",
+		"users.go":            "users",
+		"audit_users.go":      "users",
+		"shop_users.go":       "Users",
+		"escaped.go":          "../escaped",
+		"a_b.go":              "a/b",
+		"table.go":            "..",
+		"order_test_model.go": "order_test",
+	}
+	want := map[string]string{}
+	for name, content := range names {
+		want[filepath.Join("models", name)] = content
+	}
+	if !reflect.DeepEqual(written, want) {
+		t.Errorf("WriteDir wrote %v, want %v", written, want)
+	}
+}
+
+// TestWriteFile replaces the files we generated and refuses the others without -force.
+func TestWriteFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		force    bool
+		ok       bool
+	}{
+		{name: "new", ok: true},
+		{name: "generated", existing: convert.GeneratedHeader + "\n\npackage models\n", ok: true},
+		{name: "generated sql", existing: "-- schema of shop\n\n" + sqlHeader + "\nCREATE TABLE t (id int);\n", ok: true},
+		{name: "header after code", existing: "package models\n\n" + convert.GeneratedHeader + "\n"},
+		{name: "written by hand", existing: "package models\n\ntype User struct{}\n"},
+		{name: "forced", existing: "package models\n\ntype User struct{}\n", force: true, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "models", "user.go")
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := WriteFile(path, "content", tt.force)
+			if (err == nil) != tt.ok {
+				t.Fatalf("WriteFile: %v, want ok %v", err, tt.ok)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.existing
+			if tt.ok {
+				want = "content"
+			}
+			if string(content) != want {
+				t.Errorf("file has %q, want %q", content, want)
+			}
+		})
+	}
+}
+
+// TestMergeFile merges the models into a generated file with a method written by hand,
+// a later run without -merge must not replace it.
+func TestMergeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.go")
+	generated := convert.GeneratedHeader + "\n\npackage models\n\ntype Users struct {\n\tId int64 `gorm:\"column:id\"` //id\n}\n"
+	if err := WriteFile(path, generated, false); err != nil {
+		t.Fatal(err)
+	}
+	edited := generated + "\nfunc (u *Users) Hello() string { return \"hello\" }\n"
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := MergeFile(path, generated); err != nil {
+		t.Fatal(err)
+	}
+	merged, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(merged), "Hello") {
+		t.Fatalf("merged file lost the method:\n%s", merged)
+	}
+	if err := WriteFile(path, generated, false); err == nil {
+		t.Errorf("WriteFile replaced the merged file")
+	}
+}
+
+// TestExpandInputs expands directories recursively to their sql files, globs and stdin,
+// a file named twice is read once.
+func TestExpandInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a/1.sql", "a/b/2.SQL", "a/readme.md", "c.sql", "d.sql"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	join := func(names ...string) []string {
+		paths := make([]string, len(names))
+		for i, name := range names {
+			paths[i] = filepath.Join(dir, name)
+		}
+		return paths
+	}
+
+	args := append([]string{Stdin}, join("d.sql", "a", "*.sql", "a/1.sql")...)
+	args = append(args, Stdin)
+	got, err := ExpandInputs(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]string{Stdin}, join("d.sql", "a/1.sql", "a/b/2.SQL", "c.sql")...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandInputs: %v, want %v", got, want)
+	}
+
+	if _, err := ExpandInputs(join("missing.sql")); err == nil {
+		t.Errorf("ExpandInputs of a missing file succeeded")
+	}
+}
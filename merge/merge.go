@@ -0,0 +1,320 @@
+// Package merge updates the generated parts of an existing model file,
+// keeping the code written by hand.
+//
+// A field is generated if its gorm tag has a column setting, a generated field
+// is replaced by the field of the same column, or removed if the column is gone.
+// Other fields, fields marked with a `sql-to-gorm:keep` comment, methods,
+// comments and imports are kept. Tags other than gorm of a generated field are kept too.
+// A merged file which keeps code written by hand is no longer generated, its header
+// is replaced by EditedHeader.
+package merge
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/reverse"
+)
+
+// KeepMarker in the comments of a field keeps it as it is.
+const KeepMarker = "sql-to-gorm:keep"
+
+// EditedHeader replaces convert.GeneratedHeader in a merged file which keeps code written by hand,
+// so the file is not replaced by a later run without -merge.
+const EditedHeader = "// Generated by sql-to-gorm and edited by hand, update it with -merge."
+
+// edit replaces src[start:end] with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// Merge returns src with the structs of generated merged in,
+// structs only in generated are appended. The result is gofmt-ed.
+// If it keeps code of src which generated does not have, e.g. a method or a field
+// without column, its generated header is replaced by EditedHeader.
+func Merge(src, generated []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	old, err := parser.ParseFile(fset, "old.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := parser.ParseFile(fset, "generated.go", generated, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	oldStructs := structs(old)
+	handWritten := handWrittenDecls(old, structs(gen))
+	edits := make([]edit, 0)
+	for _, decl := range gen.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			newSpec := spec.(*ast.TypeSpec)
+			newStruct, ok := newSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			if oldStruct, ok := oldStructs[newSpec.Name.Name]; ok {
+				structEdits, kept := mergeStruct(src, generated, oldStruct, newStruct, offset)
+				edits = append(edits, structEdits...)
+				handWritten = handWritten || kept
+				continue
+			}
+			start := genDecl.Pos()
+			if genDecl.Doc != nil {
+				start = genDecl.Doc.Pos()
+			}
+			edits = append(edits, edit{
+				start: len(src),
+				end:   len(src),
+				text:  "\n" + string(generated[offset(start):offset(genDecl.End())]) + "\n",
+			})
+		}
+	}
+	merged := apply(src, edits)
+
+	// fix imports: add those of generated, remove those no longer used
+	file, err := parser.ParseFile(fset, "merged.go", merged, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range gen.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		// an added import is dropped again if the merged file does not use it, e.g. for a kept field
+		if astutil.AddImport(fset, file, path) && !astutil.UsesImport(file, path) {
+			astutil.DeleteImport(fset, file, path)
+		}
+	}
+	for _, spec := range old.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if astutil.UsesImport(old, path) && !astutil.UsesImport(file, path) {
+			astutil.DeleteImport(fset, file, path)
+		}
+	}
+	buf := new(bytes.Buffer)
+	if err := format.Node(buf, fset, file); err != nil {
+		return nil, err
+	}
+	if handWritten {
+		return bytes.Replace(buf.Bytes(), []byte(convert.GeneratedHeader), []byte(EditedHeader), 1), nil
+	}
+	return buf.Bytes(), nil
+}
+
+// handWrittenDecls reports whether file declares anything but imports and the structs of generated.
+func handWrittenDecls(file *ast.File, generated map[string]*ast.StructType) bool {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			return true
+		}
+		if genDecl.Tok == token.IMPORT {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || generated[typeSpec.Name.Name] == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeStruct returns the edits which update the generated fields of old to those of gen,
+// and whether it keeps fields or tags written by hand.
+func mergeStruct(src, generated []byte, old, gen *ast.StructType, offset func(token.Pos) int) ([]edit, bool) {
+	// fields of gen by column
+	newFields := map[string]*ast.Field{}
+	columns := make([]string, 0)
+	for _, f := range gen.Fields.List {
+		if column := column(f); column != "" {
+			newFields[column] = f
+			columns = append(columns, column)
+		}
+	}
+
+	edits := make([]edit, 0)
+	handWritten := false
+	insertAt := -1
+	for _, f := range old.Fields.List {
+		column := column(f)
+		if column == "" || keep(f) {
+			if column != "" {
+				delete(newFields, column)
+			}
+			handWritten = true
+			continue
+		}
+		insertAt = lineEnd(src, offset(fieldEnd(f)))
+		newField, ok := newFields[column]
+		if !ok {
+			start := f.Pos()
+			if f.Doc != nil {
+				start = f.Doc.Pos()
+			}
+			edits = append(edits, edit{start: lineStart(src, offset(start)), end: insertAt})
+			continue
+		}
+		delete(newFields, column)
+		text, kept := fieldText(src, generated, f, newField, offset)
+		edits = append(edits, edit{
+			start: offset(f.Pos()),
+			end:   offset(fieldEnd(f)),
+			text:  text,
+		})
+		handWritten = handWritten || kept
+	}
+
+	// new columns follow the last generated field, or end the struct
+	if insertAt < 0 {
+		insertAt = lineStart(src, offset(old.Fields.Closing))
+	}
+	text := new(strings.Builder)
+	for _, column := range columns {
+		if f, ok := newFields[column]; ok {
+			text.WriteString("\t" + string(generated[offset(f.Pos()):offset(fieldEnd(f))]) + "\n")
+		}
+	}
+	if text.Len() > 0 {
+		prefix := ""
+		if insertAt > 0 && src[insertAt-1] != '\n' {
+			prefix = "\n"
+		}
+		edits = append(edits, edit{start: insertAt, end: insertAt, text: prefix + text.String()})
+	}
+	return edits, handWritten
+}
+
+// fieldText returns the source of gen, with the tags other than gorm of old,
+// and whether there are such tags.
+func fieldText(src, generated []byte, old, gen *ast.Field, offset func(token.Pos) int) (string, bool) {
+	text := string(generated[offset(gen.Pos()):offset(fieldEnd(gen))])
+	if old.Tag == nil || gen.Tag == nil {
+		return text, false
+	}
+	oldTag, _ := strconv.Unquote(old.Tag.Value)
+	newTag, _ := strconv.Unquote(gen.Tag.Value)
+	kept := false
+	for _, pair := range tagPairs(oldTag) {
+		if pair[0] == "gorm" {
+			continue
+		}
+		if _, ok := reflect.StructTag(newTag).Lookup(pair[0]); !ok {
+			newTag += " " + pair[0] + ":" + strconv.Quote(pair[1])
+			kept = true
+		}
+	}
+	start := offset(gen.Tag.Pos()) - offset(gen.Pos())
+	end := offset(gen.Tag.End()) - offset(gen.Pos())
+	literal := strconv.Quote(newTag)
+	if strconv.CanBackquote(newTag) {
+		literal = "`" + newTag + "`"
+	}
+	return text[:start] + literal + text[end:], kept
+}
+
+// tagPairs splits a struct tag into its key and value pairs, in order.
+func tagPairs(tag string) [][2]string {
+	result := make([][2]string, 0)
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		i := strings.Index(tag, ":")
+		if i <= 0 || i+1 >= len(tag) || tag[i+1] != '"' {
+			return result
+		}
+		quoted, err := strconv.QuotedPrefix(tag[i+1:])
+		if err != nil {
+			return result
+		}
+		value, _ := strconv.Unquote(quoted)
+		result = append(result, [2]string{tag[:i], value})
+		tag = tag[i+1+len(quoted):]
+	}
+}
+
+// structs returns the struct types of file by name.
+func structs(file *ast.File) map[string]*ast.StructType {
+	result := map[string]*ast.StructType{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok {
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				result[spec.Name.Name] = st
+			}
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+// column returns the column setting of the gorm tag of f, empty if f is not generated.
+func column(f *ast.Field) string {
+	if f.Tag == nil || len(f.Names) == 0 {
+		return ""
+	}
+	tag, _ := strconv.Unquote(f.Tag.Value)
+	value, _ := reflect.StructTag(tag).Lookup("gorm")
+	return strings.ToLower(reverse.ParseTag(value)["COLUMN"])
+}
+
+func keep(f *ast.Field) bool {
+	for _, group := range []*ast.CommentGroup{f.Doc, f.Comment} {
+		if group != nil && strings.Contains(group.Text(), KeepMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldEnd is the end of f including its line comment.
+func fieldEnd(f *ast.Field) token.Pos {
+	if f.Comment != nil {
+		return f.Comment.End()
+	}
+	return f.End()
+}
+
+func lineStart(src []byte, offset int) int {
+	return bytes.LastIndexByte(src[:offset], '\n') + 1
+}
+
+// lineEnd returns the offset after the newline ending the line of offset.
+func lineEnd(src []byte, offset int) int {
+	if i := bytes.IndexByte(src[offset:], '\n'); i >= 0 {
+		return offset + i + 1
+	}
+	return len(src)
+}
+
+func apply(src []byte, edits []edit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	buf := new(bytes.Buffer)
+	last := 0
+	for _, e := range edits {
+		buf.Write(src[last:e.start])
+		buf.WriteString(e.text)
+		last = e.end
+	}
+	buf.Write(src[last:])
+	return buf.Bytes()
+}
@@ -0,0 +1,226 @@
+package merge
+
+import "testing"
+
+const header = "// Code generated by sql-to-gorm. DO NOT EDIT.\n\npackage models\n"
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		generated string
+		want      string
+	}{{
+		name: "new import",
+		src: `package models
+
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+`,
+		generated: header + `
+import (
+    "time"
+)
+
+type User struct {
+    Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+    CreatedAt time.Time ` + "`gorm:\"column:created_at\"`" + ` //created_at
+}
+`,
+		want: `package models
+
+import "time"
+
+type User struct {
+	Id        int64     ` + "`gorm:\"column:id\"`" + `         //id
+	CreatedAt time.Time ` + "`gorm:\"column:created_at\"`" + ` //created_at
+}
+`,
+	}, {
+		name: "hand-written code",
+		src: `package models
+
+import "strings"
+
+// User is a user.
+type User struct {
+	Id   int64  ` + "`gorm:\"column:id\"`" + ` //id
+	Name string ` + "`gorm:\"column:name;type:varchar(10)\" json:\"name\"`" + ` //name
+	// Cache is not a column.
+	Cache map[string]string ` + "`gorm:\"-\"`" + `
+}
+
+func (u *User) Upper() string { return strings.ToUpper(u.Name) }
+`,
+		generated: header + `
+type User struct {
+    Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+    Name string ` + "`gorm:\"column:name;type:varchar(64)\"`" + ` //name
+}
+`,
+		want: `package models
+
+import "strings"
+
+// User is a user.
+type User struct {
+	Id   int64  ` + "`gorm:\"column:id\"`" + `                                //id
+	Name string ` + "`gorm:\"column:name;type:varchar(64)\" json:\"name\"`" + ` //name
+	// Cache is not a column.
+	Cache map[string]string ` + "`gorm:\"-\"`" + `
+}
+
+func (u *User) Upper() string { return strings.ToUpper(u.Name) }
+`,
+	}, {
+		name: "removed column",
+		src: `package models
+
+import "time"
+
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+	// LastSeen is gone.
+	LastSeen time.Time ` + "`gorm:\"column:last_seen\"`" + ` //last_seen
+}
+`,
+		generated: header + `
+type User struct {
+    Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+`,
+		want: `package models
+
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+`,
+	}, {
+		name: "keep",
+		src: `package models
+
+type User struct {
+	Id   int64  ` + "`gorm:\"column:id\"`" + ` //id
+	Nick string ` + "`gorm:\"column:nick\"`" + ` // sql-to-gorm:keep
+}
+`,
+		generated: header + `
+import (
+    "time"
+)
+
+type User struct {
+    Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+    Nick time.Time ` + "`gorm:\"column:nick\"`" + ` //nick
+}
+`,
+		want: `package models
+
+type User struct {
+	Id   int64  ` + "`gorm:\"column:id\"`" + `   //id
+	Nick string ` + "`gorm:\"column:nick\"`" + ` // sql-to-gorm:keep
+}
+`,
+	}, {
+		name: "new model",
+		src: `package models
+
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+`,
+		generated: header + `
+type User struct {
+    Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+
+
+type Tag struct {
+    Name string ` + "`gorm:\"column:name\"`" + ` //name
+}
+`,
+		want: `package models
+
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+
+type Tag struct {
+	Name string ` + "`gorm:\"column:name\"`" + ` //name
+}
+`,
+	}, {
+		name: "generated header",
+		src: header + `
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+`,
+		generated: header + `
+type User struct {
+    Id int64 ` + "`gorm:\"column:id;type:bigint\"`" + ` //id
+}
+`,
+		want: header + `
+type User struct {
+	Id int64 ` + "`gorm:\"column:id;type:bigint\"`" + ` //id
+}
+`,
+	}, {
+		name: "edited header",
+		src: header + `
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+
+func (u *User) Hello() string { return "hello" }
+`,
+		generated: header + `
+type User struct {
+    Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+`,
+		want: EditedHeader + `
+
+package models
+
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+
+func (u *User) Hello() string { return "hello" }
+`,
+	}, {
+		name: "edited header of a tag",
+		src: header + `
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\" json:\"id\"`" + ` //id
+}
+`,
+		generated: header + `
+type User struct {
+    Id int64 ` + "`gorm:\"column:id\"`" + ` //id
+}
+`,
+		want: EditedHeader + `
+
+package models
+
+type User struct {
+	Id int64 ` + "`gorm:\"column:id\" json:\"id\"`" + ` //id
+}
+`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge([]byte(tt.src), []byte(tt.generated))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Merge =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/merge"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// isGenerated reports whether the file at path carries the header of sql-to-gorm
+// in its leading comments, files generated by other tools are not ours to replace.
+func isGenerated(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == convert.GeneratedHeader || line == sqlHeader {
+			return true, nil
+		}
+		if line != "" && !strings.HasPrefix(line, "//") && !strings.HasPrefix(line, "--") {
+			break
+		}
+	}
+	return false, scanner.Err()
+}
+
+// WriteFile writes content to path, refusing to replace a file
+// which was not generated by us unless force is set.
+func WriteFile(path string, content string, force bool) error {
+	if !force {
+		generated, err := isGenerated(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil && !generated {
+			return fmt.Errorf("%s exists and is not generated, use -force to overwrite", path)
+		}
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// MergeFile merges content into the existing go file at path, keeping the code written by hand,
+// see package merge. It writes content if the file does not exist.
+func MergeFile(path string, content string) error {
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return WriteFile(path, content, false)
+	}
+	if err != nil {
+		return err
+	}
+	merged, err := merge.Merge(src, []byte(content))
+	if err != nil {
+		return fmt.Errorf("merge %s: %w", path, err)
+	}
+	return os.WriteFile(path, merged, 0644)
+}
+
+// WriteDir writes one file per table into dir, e.g. user_info.go, with write.
+// A table named like one before in another database is written to database_table.go,
+// e.g. audit_users.go, other names taken before get a number, e.g. users2.go.
+// Characters other than letters, digits and _ are replaced, a file is never written outside dir.
+func WriteDir(dir string, s *schema.Schema, render func(io.Writer, *schema.Schema) error,
+	write func(path, content string) error) error {
+	taken := map[string]bool{}
+	for _, t := range s.Tables {
+		buf := new(bytes.Buffer)
+		if err := render(buf, &schema.Schema{Tables: []*schema.Table{t}}); err != nil {
+			return err
+		}
+		path := filepath.Join(dir, fileName(t, taken)+".go")
+		if filepath.Dir(path) != filepath.Clean(dir) {
+			return fmt.Errorf("file %s of table %s is outside %s", path, t.Name, dir)
+		}
+		if err := write(path, buf.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileName returns the name of the file of t without extension, not in taken,
+// and adds it to taken. Names are lower case, file systems may ignore case.
+func fileName(t *schema.Table, taken map[string]bool) string {
+	name := baseName(t.Name)
+	if taken[name] && t.Database != "" {
+		name = baseName(t.Database + "_" + t.Name)
+	}
+	result := name
+	for i := 2; taken[result]; i++ {
+		result = name + strconv.Itoa(i)
+	}
+	taken[result] = true
+	return result
+}
+
+// baseName returns name in lower case with the characters other than a-z, 0-9 and _ replaced by _,
+// so it names a file in the directory, e.g. a_b of a/b. Leading _, e.g. of ../users, are left out
+// and _model is added to a name ending with _test, go would ignore the file or take it for a test.
+func baseName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(name))
+	name = strings.TrimLeft(name, "_")
+	switch {
+	case name == "":
+		return "table"
+	case strings.HasSuffix(name, "_test"):
+		return name + "_model"
+	}
+	return name
+}
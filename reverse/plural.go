@@ -0,0 +1,79 @@
+package reverse
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+)
+
+// TableName returns the table gorm names a struct without a TableName method after,
+// the plural of its snake case name like the default naming strategy of gorm,
+// e.g. users of User and user_infos of UserInfo.
+func TableName(structName string) string {
+	return plural(convert.CamelToSnake(structName))
+}
+
+// inflection is a rule of plural, the suffix of a word matching pattern is replaced.
+type inflection struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// uncountables are the words which are their own plural, the whole name must be one of them.
+var uncountables = []string{"equipment", "information", "rice", "money", "species", "series", "fish", "sheep", "jeans", "police"}
+
+// irregulars are the words whose plural follows no rule, they end the name.
+var irregulars = [][2]string{
+	{"person", "people"},
+	{"man", "men"},
+	{"child", "children"},
+	{"sex", "sexes"},
+	{"move", "moves"},
+	{"mombie", "mombies"},
+}
+
+// plurals are the rules of the inflection package gorm uses, the last matching one applies.
+var plurals = []inflection{
+	{regexp.MustCompile(`$`), "s"},
+	{regexp.MustCompile(`s$`), "s"},
+	{regexp.MustCompile(`^(ax|test)is$`), "${1}es"},
+	{regexp.MustCompile(`(octop|vir)us$`), "${1}i"},
+	{regexp.MustCompile(`(octop|vir)i$`), "${1}i"},
+	{regexp.MustCompile(`(alias|status|campus)$`), "${1}es"},
+	{regexp.MustCompile(`(bu)s$`), "${1}ses"},
+	{regexp.MustCompile(`(buffal|tomat)o$`), "${1}oes"},
+	{regexp.MustCompile(`([ti])um$`), "${1}a"},
+	{regexp.MustCompile(`([ti])a$`), "${1}a"},
+	{regexp.MustCompile(`sis$`), "ses"},
+	{regexp.MustCompile(`(?:([^f])fe|([lr])f)$`), "${1}${2}ves"},
+	{regexp.MustCompile(`(hive)$`), "${1}s"},
+	{regexp.MustCompile(`([^aeiouy]|qu)y$`), "${1}ies"},
+	{regexp.MustCompile(`(x|ch|ss|sh)$`), "${1}es"},
+	{regexp.MustCompile(`(matr|vert|ind)(?:ix|ex)$`), "${1}ices"},
+	{regexp.MustCompile(`^(m|l)ouse$`), "${1}ice"},
+	{regexp.MustCompile(`^(m|l)ice$`), "${1}ice"},
+	{regexp.MustCompile(`^(ox)$`), "${1}en"},
+	{regexp.MustCompile(`^(oxen)$`), "${1}"},
+	{regexp.MustCompile(`(quiz)$`), "${1}zes"},
+}
+
+// plural returns the plural of a lower case name, e.g. categories of category.
+func plural(name string) string {
+	for _, word := range uncountables {
+		if name == word {
+			return name
+		}
+	}
+	for _, irregular := range irregulars {
+		if strings.HasSuffix(name, irregular[0]) {
+			return strings.TrimSuffix(name, irregular[0]) + irregular[1]
+		}
+	}
+	for i := len(plurals) - 1; i >= 0; i-- {
+		if rule := plurals[i]; rule.pattern.MatchString(name) {
+			return rule.pattern.ReplaceAllString(name, rule.replacement)
+		}
+	}
+	return name
+}
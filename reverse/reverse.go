@@ -0,0 +1,538 @@
+// Package reverse builds the schema model from go structs with gorm tags,
+// using the type mapping of package convert in the other direction.
+package reverse
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+type Option struct {
+	// Dir is the directory to load the packages in, empty for the current directory.
+	Dir string
+	// Warnf receives the warnings, nil to discard them.
+	Warnf func(format string, args ...interface{})
+}
+
+func (o Option) warnf(format string, args ...interface{}) {
+	if o.Warnf == nil {
+		return
+	}
+	o.Warnf(format, args...)
+}
+
+// Load loads the packages matching patterns, e.g. ./internal/models/...,
+// and returns a table for each struct with gorm tags.
+func Load(opts Option, patterns ...string) (*schema.Schema, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo,
+		Dir: opts.Dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	var errList convert.ErrorList
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			errList = append(errList, e)
+		}
+	})
+	if err := errList.Err(); err != nil {
+		return nil, err
+	}
+
+	s := &schema.Schema{}
+	for _, pkg := range pkgs {
+		for _, t := range FromPackage(pkg, opts) {
+			if old := s.Put(t); old != nil {
+				opts.warnf("duplicate table %s: defined in %s and %s, using the latter",
+					t.Name, old.Source, t.Source)
+			}
+		}
+	}
+	return s, nil
+}
+
+// FromPackage returns the tables of the models declared in pkg, in declaration order.
+func FromPackage(pkg *packages.Package, opts Option) []*schema.Table {
+	tableNames := TableNames(pkg.Syntax)
+	bases := embeddedModels(pkg)
+	result := make([]*schema.Table, 0)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				obj, ok := pkg.TypesInfo.Defs[typeSpec.Name].(*types.TypeName)
+				if !ok {
+					continue
+				}
+				st, ok := obj.Type().Underlying().(*types.Struct)
+				if !ok || !isModel(st) || (bases[obj] && tableNames[obj.Name()] == "") {
+					continue
+				}
+				name := tableNames[obj.Name()]
+				if name == "" {
+					name = TableName(obj.Name())
+				}
+				b := &builder{
+					table: &schema.Table{
+						Name:   name,
+						Source: pkg.Fset.Position(typeSpec.Pos()).String(),
+					},
+					opts: opts,
+				}
+				b.addFields(st, "")
+				b.finish()
+				result = append(result, b.table)
+			}
+		}
+	}
+	return result
+}
+
+// embeddedModels returns the structs embedded in other structs of pkg,
+// such base structs like gorm.Model are not tables unless they have a TableName.
+func embeddedModels(pkg *packages.Package) map[*types.TypeName]bool {
+	result := map[*types.TypeName]bool{}
+	for _, obj := range pkg.TypesInfo.Defs {
+		tn, ok := obj.(*types.TypeName)
+		if !ok {
+			continue
+		}
+		st, ok := tn.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			if !f.Anonymous() {
+				continue
+			}
+			t := f.Type()
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem()
+			}
+			if named, ok := t.(*types.Named); ok {
+				result[named.Obj()] = true
+			}
+		}
+	}
+	return result
+}
+
+// isModel reports whether a field of st, or of its embedded structs, has a gorm tag.
+func isModel(st *types.Struct) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		if _, ok := lookupTag(st.Tag(i), "gorm"); ok {
+			return true
+		}
+		if embedded, ok := embeddedStruct(st.Field(i)); ok && isModel(embedded) {
+			return true
+		}
+	}
+	return false
+}
+
+func embeddedStruct(f *types.Var) (*types.Struct, bool) {
+	if !f.Anonymous() {
+		return nil, false
+	}
+	t := f.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	return st, ok
+}
+
+// TableNames finds `func (T) TableName() string { return "name" }`
+// and returns the table names by type name.
+func TableNames(files []*ast.File) map[string]string {
+	result := map[string]string{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "TableName" || fn.Recv == nil || len(fn.Recv.List) != 1 ||
+				fn.Body == nil || len(fn.Body.List) != 1 {
+				continue
+			}
+			recv := fn.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			ident, ok := recv.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+			lit, ok := ret.Results[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+			if name, err := strconv.Unquote(lit.Value); err == nil {
+				result[ident.Name] = name
+			}
+		}
+	}
+	return result
+}
+
+type builder struct {
+	table *schema.Table
+	opts  Option
+	// hasPrimaryKey is set if a field is tagged primaryKey
+	hasPrimaryKey bool
+	// id is the column of the field named ID, the primary key by convention
+	id *schema.Column
+	// noAutoIncrement is set if a field is tagged autoIncrement:false
+	noAutoIncrement bool
+	// priorities are the priorities of the index columns, 10 if not tagged like gorm
+	priorities map[*schema.IndexColumn]int
+}
+
+func (b *builder) addFields(st *types.Struct, prefix string) {
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tag, _ := lookupTag(st.Tag(i), "gorm")
+		settings := ParseTag(tag)
+		if _, ok := settings["-"]; ok {
+			continue
+		}
+		if embedded, ok := embeddedStruct(f); ok {
+			if _, ok := sqlType(f.Type(), settings); !ok {
+				b.addFields(embedded, prefix+settings["EMBEDDEDPREFIX"])
+				continue
+			}
+		}
+		if _, ok := settings["EMBEDDED"]; ok {
+			if embedded, ok := f.Type().Underlying().(*types.Struct); ok {
+				b.addFields(embedded, prefix+settings["EMBEDDEDPREFIX"])
+				continue
+			}
+		}
+		if !f.Exported() {
+			continue
+		}
+		b.addField(f, tag, settings, prefix)
+	}
+}
+
+func (b *builder) addField(f *types.Var, tag string, settings map[string]string, prefix string) {
+	dataType, ok := sqlType(f.Type(), settings)
+	if !ok {
+		if !isAssociation(f.Type()) {
+			b.opts.warnf("%s: field %s: unknown type %s, skipped",
+				b.table.Name, f.Name(), typeString(f.Type()))
+		}
+		return
+	}
+	// like gorm migrator, columns are nullable unless tagged not null
+	col := &schema.Column{
+		Name:     settings["COLUMN"],
+		Type:     dataType,
+		Nullable: true,
+	}
+	if col.Name == "" {
+		col.Name = prefix + convert.CamelToSnake(f.Name())
+	}
+	if size, ok := settings["SIZE"]; ok {
+		if n, err := strconv.Atoi(size); err == nil {
+			col.Type.Length = &n
+		}
+	}
+	if precision, ok := settings["PRECISION"]; ok {
+		if n, err := strconv.Atoi(precision); err == nil {
+			col.Type.Length = &n
+		}
+	}
+	if scale, ok := settings["SCALE"]; ok {
+		if n, err := strconv.Atoi(scale); err == nil {
+			col.Type.Scale = &n
+		}
+	}
+	if _, ok := settings["NOT NULL"]; ok {
+		col.Nullable = false
+	}
+	if value, ok := settings["DEFAULT"]; ok {
+		col.Default = &value
+	}
+	if value, ok := settings["COMMENT"]; ok {
+		col.Comment = value
+	}
+	if value, ok := settings["AUTOINCREMENT"]; ok {
+		col.AutoIncrement = value != "false"
+		b.noAutoIncrement = value == "false"
+	}
+	if _, ok := settings["PRIMARYKEY"]; ok {
+		b.hasPrimaryKey = true
+		col.PrimaryKey = true
+		col.Nullable = false
+	} else if f.Name() == "ID" && b.id == nil {
+		b.id = col
+	}
+	b.table.Columns = append(b.table.Columns, col)
+
+	if _, ok := settings["UNIQUE"]; ok {
+		col.Unique = true
+	}
+	for _, idx := range IndexTags(tag, b.table.Name, col.Name) {
+		b.addIndex(idx)
+	}
+}
+
+// IndexTag is an index declared by the gorm tag of a field, e.g. index:idx_name,priority:2.
+type IndexTag struct {
+	// Kind is one of schema.IndexUnique, schema.IndexNormal, schema.IndexFulltext and schema.IndexSpatial.
+	Kind string
+	Name string
+	// Column is the key part of the field.
+	Column *schema.IndexColumn
+	// Priority orders the columns of a composite index, 10 if not given like gorm.
+	Priority int
+}
+
+// IndexTags returns the indexes declared by the gorm tag of the field of column in table,
+// unique is an index named after the column. A field may be in several indexes,
+// so the settings are read from the tag and not from ParseTag.
+func IndexTags(tag, table, column string) []IndexTag {
+	result := make([]IndexTag, 0)
+	for _, setting := range splitTag(tag) {
+		key, value, _ := strings.Cut(setting, ":")
+		idx := IndexTag{Kind: schema.IndexNormal, Column: &schema.IndexColumn{Name: column}, Priority: 10}
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "UNIQUE":
+			idx.Kind, idx.Name, idx.Priority = schema.IndexUnique, column, 0
+			result = append(result, idx)
+			continue
+		case "INDEX":
+		case "UNIQUEINDEX":
+			idx.Kind = schema.IndexUnique
+		default:
+			continue
+		}
+		options := indexOptions(value)
+		switch class := strings.ToUpper(options["CLASS"]); class {
+		case schema.IndexFulltext, schema.IndexSpatial:
+			idx.Kind = class
+		}
+		if _, ok := options["UNIQUE"]; ok {
+			idx.Kind = schema.IndexUnique
+		}
+		idx.Name = IndexName(value, table, column)
+		idx.Column.Desc = strings.EqualFold(options["SORT"], "desc")
+		if n, err := strconv.Atoi(options["LENGTH"]); err == nil {
+			idx.Column.Length = &n
+		}
+		if n, err := strconv.Atoi(options["PRIORITY"]); err == nil {
+			idx.Priority = n
+		}
+		result = append(result, idx)
+	}
+	return result
+}
+
+// IndexName returns the name of an index tag, index:name,sort:desc,
+// gorm names an index without name idx_table_column.
+func IndexName(value, table, column string) string {
+	name := strings.TrimSpace(strings.Split(value, ",")[0])
+	if name == "" || strings.Contains(name, ":") {
+		return fmt.Sprintf("idx_%s_%s", table, column)
+	}
+	return name
+}
+
+// indexOptions parses the options of an index tag, e.g. class:FULLTEXT and priority:2
+// of index:idx_name,class:FULLTEXT,priority:2, into upper case keys and their values.
+func indexOptions(value string) map[string]string {
+	options := map[string]string{}
+	for _, option := range strings.Split(value, ",") {
+		key, value, ok := strings.Cut(option, ":")
+		if !ok {
+			if strings.EqualFold(strings.TrimSpace(key), "unique") {
+				options["UNIQUE"] = ""
+			}
+			continue
+		}
+		options[strings.ToUpper(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return options
+}
+
+// addIndex adds the column of tag to the index with its name, indexes with the same name
+// are composite and their columns are in the order of their priority.
+func (b *builder) addIndex(tag IndexTag) {
+	idx := b.table.Index(tag.Name)
+	if idx == nil {
+		idx = &schema.Index{Name: tag.Name, Kind: tag.Kind}
+		b.table.Indexes = append(b.table.Indexes, idx)
+	}
+	if b.priorities == nil {
+		b.priorities = map[*schema.IndexColumn]int{}
+	}
+	b.priorities[tag.Column] = tag.Priority
+	idx.Columns = append(idx.Columns, tag.Column)
+	sort.SliceStable(idx.Columns, func(i, j int) bool {
+		return b.priorities[idx.Columns[i]] < b.priorities[idx.Columns[j]]
+	})
+}
+
+// finish adds the primary key, a field named ID is the primary key
+// if no field is tagged primaryKey.
+func (b *builder) finish() {
+	if !b.hasPrimaryKey && b.id != nil {
+		b.id.PrimaryKey = true
+		b.id.Nullable = false
+	}
+	pk := &schema.Index{Kind: schema.IndexPrimary}
+	for _, col := range b.table.Columns {
+		if !col.PrimaryKey {
+			continue
+		}
+		pk.Columns = append(pk.Columns, &schema.IndexColumn{Name: col.Name})
+	}
+	if len(pk.Columns) == 0 {
+		return
+	}
+	// gorm makes a single integer primary key auto increment
+	if len(pk.Columns) == 1 {
+		col := b.table.Column(pk.Columns[0].Name)
+		if convert.LookupType(col.Type).Name == "int64" && col.Default == nil && !b.noAutoIncrement {
+			col.AutoIncrement = true
+		}
+	}
+	b.table.Indexes = append([]*schema.Index{pk}, b.table.Indexes...)
+}
+
+// sqlType returns the sql type of a field, the type setting of the tag wins.
+func sqlType(t types.Type, settings map[string]string) (schema.DataType, bool) {
+	if value, ok := settings["TYPE"]; ok {
+		return ParseType(value), true
+	}
+	if dataType, ok := convert.ReverseType(typeString(t)); ok {
+		return dataType, true
+	}
+	// named types like `type Status int8` use their underlying type
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if basic, ok := t.Underlying().(*types.Basic); ok {
+		return convert.ReverseType(basic.Name())
+	}
+	return schema.DataType{}, false
+}
+
+// isAssociation reports whether t is a struct, a slice or a map,
+// such fields are relations or serialized by gorm.
+func isAssociation(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	switch t.Underlying().(type) {
+	case *types.Struct, *types.Slice, *types.Map, *types.Interface:
+		return true
+	}
+	return false
+}
+
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		return p.Name()
+	})
+}
+
+// ParseType parses a sql type like bigint(20) unsigned, e.g. of a type tag.
+func ParseType(s string) schema.DataType {
+	s = strings.TrimSpace(s)
+	t := schema.DataType{Raw: s}
+	lower := strings.ToLower(s)
+	end := strings.IndexAny(lower, "( ")
+	if end < 0 {
+		end = len(lower)
+	}
+	t.Name = lower[:end]
+	if open := strings.Index(lower, "("); open >= 0 {
+		if close := strings.Index(lower[open:], ")"); close > 0 {
+			args := strings.Split(s[open+1:open+close], ",")
+			if t.Name == "enum" || t.Name == "set" {
+				for _, arg := range args {
+					t.Values = append(t.Values, strings.Trim(strings.TrimSpace(arg), `'"`))
+				}
+			} else {
+				if n, err := strconv.Atoi(strings.TrimSpace(args[0])); err == nil {
+					t.Length = &n
+				}
+				if len(args) > 1 {
+					if n, err := strconv.Atoi(strings.TrimSpace(args[1])); err == nil {
+						t.Scale = &n
+					}
+				}
+			}
+		}
+	}
+	for _, word := range strings.Fields(lower) {
+		switch word {
+		case "unsigned":
+			t.Unsigned = true
+		case "zerofill":
+			t.Zerofill = true
+		}
+	}
+	return t
+}
+
+// ParseTag parses a gorm tag like column:id;primaryKey;not null into
+// upper case keys and their values, like gorm does.
+func ParseTag(tag string) map[string]string {
+	settings := map[string]string{}
+	for _, name := range splitTag(tag) {
+		values := strings.SplitN(name, ":", 2)
+		key := strings.TrimSpace(strings.ToUpper(values[0]))
+		if key == "" {
+			continue
+		}
+		// gorm accepts both primaryKey and primary_key
+		key = strings.ReplaceAll(key, "_", "")
+		if key == "NOTNULL" {
+			key = "NOT NULL"
+		}
+		if len(values) == 2 {
+			settings[key] = strings.TrimSpace(values[1])
+		} else {
+			settings[key] = ""
+		}
+	}
+	return settings
+}
+
+// splitTag splits a gorm tag into its settings, a backslash escapes the separator.
+func splitTag(tag string) []string {
+	names := strings.Split(strings.ReplaceAll(tag, `\;`, "\x00"), ";")
+	for i := range names {
+		names[i] = strings.ReplaceAll(names[i], "\x00", ";")
+	}
+	return names
+}
+
+func lookupTag(tag, key string) (string, bool) {
+	return reflect.StructTag(tag).Lookup(key)
+}
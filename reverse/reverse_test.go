@@ -0,0 +1,126 @@
+package reverse_test
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/er1c-zh/sql-to-gorm/convert"
+	"github.com/er1c-zh/sql-to-gorm/ddl"
+	"github.com/er1c-zh/sql-to-gorm/reverse"
+	"github.com/er1c-zh/sql-to-gorm/schema"
+)
+
+// TestRoundTrip generates the models of sql, reads them back with Load and compares
+// the columns, primary keys and indexes, which the tags of the models keep.
+func TestRoundTrip(t *testing.T) {
+	tests := map[string]string{
+		"repro": `CREATE TABLE users (
+			id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			name varchar(64) NOT NULL,
+			created_at timestamp DEFAULT CURRENT_TIMESTAMP,
+			KEY idx_name(name)
+		);`,
+		"keys": `CREATE TABLE order_items (
+			order_id bigint NOT NULL,
+			sku char(12) NOT NULL,
+			seq int NOT NULL,
+			title text,
+			email varchar(320) UNIQUE,
+			price decimal(10,2) unsigned NOT NULL DEFAULT '0.00',
+			state enum('new','paid') NOT NULL DEFAULT 'new',
+			note varchar(32) DEFAULT 'a;b"c',
+			placed_at datetime(3) NULL,
+			PRIMARY KEY (order_id, sku),
+			UNIQUE KEY uk_seq (seq, order_id),
+			KEY idx_placed (placed_at DESC, note(8)),
+			FULLTEXT KEY ft_title (title)
+		);`,
+		"manual id": `CREATE TABLE counters (
+			id int NOT NULL PRIMARY KEY,
+			hits bigint NOT NULL DEFAULT 0
+		);`,
+	}
+	for name, sql := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := convert.Convert(strings.NewReader(sql), convert.DefaultOption())
+			if err != nil {
+				t.Fatal(err)
+			}
+			dir := t.TempDir()
+			writeModels(t, dir, s)
+			got, err := reverse.Load(reverse.Option{
+				Dir: dir,
+				Warnf: func(format string, args ...interface{}) {
+					t.Errorf("unexpected warning: "+format, args...)
+				},
+			}, ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Tables) != len(s.Tables) {
+				t.Fatalf("got %d tables, want %d", len(got.Tables), len(s.Tables))
+			}
+			for i, want := range s.Tables {
+				if g, w := definitions(got.Tables[i]), definitions(want); g != w {
+					t.Errorf("table %s:\n got %s\nwant %s", want.Name, g, w)
+				}
+			}
+		})
+	}
+}
+
+func writeModels(t *testing.T, dir string, s *schema.Schema) {
+	t.Helper()
+	f, err := os.Create(filepath.Join(dir, "models.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := convert.Render(s, f, convert.DefaultOption()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module models\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// definitions returns the columns and the sorted indexes of table, the order of indexes
+// follows the fields of the model.
+func definitions(table *schema.Table) string {
+	lines := []string{table.Name}
+	for _, c := range table.Columns {
+		lines = append(lines, ddl.Column(c))
+	}
+	indexes := make([]string, 0, len(table.Indexes))
+	for _, idx := range table.Indexes {
+		indexes = append(indexes, ddl.Index(idx))
+	}
+	sort.Strings(indexes)
+	return strings.Join(append(lines, indexes...), "\n     ")
+}
+
+func TestTableName(t *testing.T) {
+	tests := map[string]string{
+		"User":       "users",
+		"UserInfo":   "user_infos",
+		"Users":      "users",
+		"OrderItems": "order_items",
+		"Category":   "categories",
+		"Address":    "addresses",
+		"Box":        "boxes",
+		"Status":     "statuses",
+		"Person":     "people",
+		"SalesMan":   "sales_men",
+		"Fish":       "fish",
+		"Wolf":       "wolves",
+		"UserID":     "user_ids",
+	}
+	for name, want := range tests {
+		if got := reverse.TableName(name); got != want {
+			t.Errorf("TableName(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
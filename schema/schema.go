@@ -0,0 +1,523 @@
+// Package schema is the dialect-neutral model of parsed sql tables.
+//
+// Parsers fill a Schema and renderers consume it, every value keeps the sql
+// spelling so the model can be dumped as JSON to see exactly what was parsed.
+package schema
+
+import "strings"
+
+// Schema is a set of tables in definition order.
+type Schema struct {
+	Tables []*Table `json:"tables"`
+}
+
+// Table returns the table with name in any database, nil if not found.
+// Names are compared case-insensitively.
+func (s *Schema) Table(name string) *Table {
+	return s.Lookup("", name)
+}
+
+// Lookup returns the table database.name, nil if not found.
+// A table without database matches any database and an empty database
+// matches any table, but a table of the same database is preferred.
+func (s *Schema) Lookup(database, name string) *Table {
+	if i := s.index(database, name); i >= 0 {
+		return s.Tables[i]
+	}
+	return nil
+}
+
+// Put adds t, replacing the table of the same name, see Lookup.
+// The replaced table is returned.
+func (s *Schema) Put(t *Table) *Table {
+	if i := s.index(t.Database, t.Name); i >= 0 {
+		old := s.Tables[i]
+		s.Tables[i] = t
+		return old
+	}
+	s.Tables = append(s.Tables, t)
+	return nil
+}
+
+// Remove removes the table database.name, see Lookup, the removed table is returned.
+func (s *Schema) Remove(database, name string) *Table {
+	i := s.index(database, name)
+	if i < 0 {
+		return nil
+	}
+	t := s.Tables[i]
+	s.Tables = append(s.Tables[:i], s.Tables[i+1:]...)
+	return t
+}
+
+func (s *Schema) index(database, name string) int {
+	found := -1
+	for i, t := range s.Tables {
+		if !strings.EqualFold(t.Name, name) {
+			continue
+		}
+		if strings.EqualFold(t.Database, database) {
+			return i
+		}
+		if found < 0 && (database == "" || t.Database == "") {
+			found = i
+		}
+	}
+	return found
+}
+
+type Table struct {
+	Name string `json:"name"`
+	// Database is the qualifier of a schema-qualified name, e.g. db of db.t.
+	Database    string        `json:"database,omitempty"`
+	Columns     []*Column     `json:"columns"`
+	Indexes     []*Index      `json:"indexes,omitempty"`
+	Constraints []*Constraint `json:"constraints,omitempty"`
+	Options     []*Option     `json:"options,omitempty"`
+	Comment     string        `json:"comment,omitempty"`
+	// Dialect is the dialect of the DDL the table is read from, empty for mysql.
+	Dialect string `json:"dialect,omitempty"`
+	// Source is the input which defines the table.
+	Source string `json:"source,omitempty"`
+}
+
+// QualifiedName is Database.Name, or Name if the table is not qualified.
+func (t *Table) QualifiedName() string {
+	if t.Database == "" {
+		return t.Name
+	}
+	return t.Database + "." + t.Name
+}
+
+// Column returns the column with name, nil if not found.
+func (t *Table) Column(name string) *Column {
+	if i := t.ColumnIndex(name); i >= 0 {
+		return t.Columns[i]
+	}
+	return nil
+}
+
+// ColumnIndex returns the position of the column with name, -1 if not found.
+func (t *Table) ColumnIndex(name string) int {
+	for i, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddColumn inserts c after the column named after,
+// at the beginning if first is set, or at the end if after is empty.
+func (t *Table) AddColumn(c *Column, first bool, after string) {
+	pos := len(t.Columns)
+	if first {
+		pos = 0
+	} else if i := t.ColumnIndex(after); after != "" && i >= 0 {
+		pos = i + 1
+	}
+	t.Columns = append(t.Columns, nil)
+	copy(t.Columns[pos+1:], t.Columns[pos:])
+	t.Columns[pos] = c
+}
+
+// DropColumn removes the column with name and its parts of indexes,
+// indexes without columns left are removed too. Foreign keys of the column
+// and checks which refer to it are removed, as they would be invalid.
+func (t *Table) DropColumn(name string) *Column {
+	i := t.ColumnIndex(name)
+	if i < 0 {
+		return nil
+	}
+	c := t.Columns[i]
+	t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+
+	indexes := t.Indexes[:0]
+	for _, idx := range t.Indexes {
+		columns := idx.Columns[:0]
+		for _, col := range idx.Columns {
+			if !strings.EqualFold(col.Name, name) {
+				columns = append(columns, col)
+			}
+		}
+		idx.Columns = columns
+		if len(idx.Columns) > 0 {
+			indexes = append(indexes, idx)
+		}
+	}
+	t.Indexes = indexes
+
+	constraints := t.Constraints[:0]
+	for _, constraint := range t.Constraints {
+		if !constraint.refers(name) {
+			constraints = append(constraints, constraint)
+		}
+	}
+	t.Constraints = constraints
+	return c
+}
+
+// RenameColumn renames a column and its references in indexes and constraints.
+func (t *Table) RenameColumn(from, to string) *Column {
+	c := t.Column(from)
+	if c == nil {
+		return nil
+	}
+	c.Name = to
+	for _, idx := range t.Indexes {
+		for _, col := range idx.Columns {
+			if strings.EqualFold(col.Name, from) {
+				col.Name = to
+			}
+		}
+	}
+	for _, constraint := range t.Constraints {
+		for i, col := range constraint.Columns {
+			if strings.EqualFold(col, from) {
+				constraint.Columns[i] = to
+			}
+		}
+	}
+	return c
+}
+
+// Index returns the index with name, nil if not found.
+func (t *Table) Index(name string) *Index {
+	for _, idx := range t.Indexes {
+		if strings.EqualFold(idx.Name, name) {
+			return idx
+		}
+	}
+	return nil
+}
+
+// DropIndex removes the index with name, the removed index is returned.
+func (t *Table) DropIndex(name string) *Index {
+	for i, idx := range t.Indexes {
+		if strings.EqualFold(idx.Name, name) {
+			t.Indexes = append(t.Indexes[:i], t.Indexes[i+1:]...)
+			return idx
+		}
+	}
+	return nil
+}
+
+// Constraint returns the constraint with name, nil if not found.
+func (t *Table) Constraint(name string) *Constraint {
+	for _, c := range t.Constraints {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// DropConstraint removes the constraint with name, the removed one is returned.
+func (t *Table) DropConstraint(name string) *Constraint {
+	for i, c := range t.Constraints {
+		if strings.EqualFold(c.Name, name) {
+			t.Constraints = append(t.Constraints[:i], t.Constraints[i+1:]...)
+			return c
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the primary key, nil if the table has none.
+func (t *Table) PrimaryKey() *Index {
+	for _, idx := range t.Indexes {
+		if idx.Kind == IndexPrimary {
+			return idx
+		}
+	}
+	return nil
+}
+
+// SetOption sets the table option with name, adding it if not present.
+func (t *Table) SetOption(name, value string) {
+	t.Options = setOption(t.Options, name, value)
+}
+
+// Option returns the value of the table option with name.
+func (t *Table) Option(name string) (string, bool) {
+	return option(t.Options, name)
+}
+
+type Column struct {
+	Name     string   `json:"name"`
+	Type     DataType `json:"type"`
+	Nullable bool     `json:"nullable"`
+	// Default is the default value as written in sql, e.g. 'a', 0, NULL
+	// or CURRENT_TIMESTAMP, nil if there is none.
+	Default       *string `json:"default,omitempty"`
+	OnUpdate      string  `json:"on_update,omitempty"`
+	AutoIncrement bool    `json:"auto_increment,omitempty"`
+	PrimaryKey    bool    `json:"primary_key,omitempty"`
+	Unique        bool    `json:"unique,omitempty"`
+	// Generated is the expression of a generated column.
+	Generated string `json:"generated,omitempty"`
+	Stored    bool   `json:"stored,omitempty"`
+	Collation string `json:"collation,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+	// Invisible columns are left out of SELECT *.
+	Invisible bool `json:"invisible,omitempty"`
+	// SystemTime is ROW START or ROW END for the period columns of a mariadb
+	// system-versioned table, their values are set by the server.
+	SystemTime string `json:"system_time,omitempty"`
+	// Versioning is WITH or WITHOUT SYSTEM VERSIONING of a column
+	// versioned unlike its table, empty if it follows the table.
+	Versioning string `json:"versioning,omitempty"`
+	// Options are the attributes of a single dialect, e.g. AUTO_RANDOM=5 of a tidb column.
+	Options []*Option `json:"options,omitempty"`
+}
+
+// SetOption sets the column option with name, adding it if not present.
+func (c *Column) SetOption(name, value string) {
+	c.Options = setOption(c.Options, name, value)
+}
+
+// Option returns the value of the column option with name.
+func (c *Column) Option(name string) (string, bool) {
+	return option(c.Options, name)
+}
+
+type DataType struct {
+	// Name is the lower case type name, e.g. varchar, bigint.
+	Name string `json:"name"`
+	// Length is the length or precision, e.g. 20 of bigint(20) or 10 of decimal(10,2).
+	Length *int `json:"length,omitempty"`
+	// Scale is the number of decimals, e.g. 2 of decimal(10,2).
+	Scale    *int `json:"scale,omitempty"`
+	Unsigned bool `json:"unsigned,omitempty"`
+	Zerofill bool `json:"zerofill,omitempty"`
+	// Values are the members of enum and set.
+	Values  []string `json:"values,omitempty"`
+	Charset string   `json:"charset,omitempty"`
+	// Raw is the type as written in sql.
+	Raw string `json:"raw"`
+}
+
+const (
+	IndexPrimary  = "PRIMARY"
+	IndexUnique   = "UNIQUE"
+	IndexNormal   = "INDEX"
+	IndexFulltext = "FULLTEXT"
+	IndexSpatial  = "SPATIAL"
+)
+
+type Index struct {
+	Name string `json:"name,omitempty"`
+	// Kind is one of IndexPrimary, IndexUnique, IndexNormal, IndexFulltext and IndexSpatial.
+	Kind    string         `json:"kind"`
+	Columns []*IndexColumn `json:"columns"`
+	// Using is the index type, e.g. BTREE.
+	Using   string `json:"using,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// Clustered is CLUSTERED or NONCLUSTERED for a tidb primary key or a SQL Server key or index,
+	// empty if not given.
+	Clustered string `json:"clustered,omitempty"`
+}
+
+// ColumnNames returns the names of the indexed columns.
+func (idx *Index) ColumnNames() []string {
+	names := make([]string, 0, len(idx.Columns))
+	for _, c := range idx.Columns {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// Functional reports whether a key part of idx is an expression.
+func (idx *Index) Functional() bool {
+	for _, c := range idx.Columns {
+		if c.Expression != "" {
+			return true
+		}
+	}
+	return false
+}
+
+type IndexColumn struct {
+	// Name is the column, empty for a functional key part.
+	Name string `json:"name"`
+	// Length is the prefix length, e.g. 10 of name(10).
+	Length *int `json:"length,omitempty"`
+	Desc   bool `json:"desc,omitempty"`
+	// Expression is the expression of a functional key part, e.g. lower(email).
+	Expression string `json:"expression,omitempty"`
+}
+
+const (
+	ConstraintForeignKey = "FOREIGN KEY"
+	ConstraintCheck      = "CHECK"
+)
+
+type Constraint struct {
+	Name string `json:"name,omitempty"`
+	// Type is ConstraintForeignKey or ConstraintCheck.
+	Type    string   `json:"type"`
+	Columns []string `json:"columns,omitempty"`
+	// RefTable and RefColumns are the referenced columns of a foreign key.
+	RefTable   string   `json:"ref_table,omitempty"`
+	RefColumns []string `json:"ref_columns,omitempty"`
+	OnDelete   string   `json:"on_delete,omitempty"`
+	OnUpdate   string   `json:"on_update,omitempty"`
+	// Check is the expression of a check constraint.
+	Check string `json:"check,omitempty"`
+	// NotEnforced is set for a check constraint which is not enforced.
+	NotEnforced bool `json:"not_enforced,omitempty"`
+}
+
+// refers reports whether c is on the column with name, or whether its check expression names it.
+func (c *Constraint) refers(name string) bool {
+	for _, col := range c.Columns {
+		if strings.EqualFold(col, name) {
+			return true
+		}
+	}
+	if c.Type != ConstraintCheck {
+		return false
+	}
+	for _, word := range identifiers(c.Check) {
+		if strings.EqualFold(word, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// identifiers returns the words and quoted names of the sql expression expr,
+// string literals are skipped.
+func identifiers(expr string) []string {
+	var words []string
+	for i := 0; i < len(expr); i++ {
+		switch ch := expr[i]; {
+		case ch == '\'':
+			for i++; i < len(expr) && expr[i] != '\''; i++ {
+				if expr[i] == '\\' {
+					i++
+				}
+			}
+		case ch == '`' || ch == '"' || ch == '[':
+			end := ch
+			if ch == '[' {
+				end = ']'
+			}
+			j := strings.IndexByte(expr[i+1:], end)
+			if j < 0 {
+				return append(words, expr[i+1:])
+			}
+			words = append(words, expr[i+1:i+1+j])
+			i += j + 1
+		case isWord(ch):
+			j := i
+			for j < len(expr) && isWord(expr[j]) {
+				j++
+			}
+			words = append(words, expr[i:j])
+			i = j - 1
+		}
+	}
+	return words
+}
+
+func isWord(ch byte) bool {
+	return ch == '_' || ch == '$' || ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= 0x80
+}
+
+// Option is a table option, e.g. ENGINE=InnoDB, or a column option, e.g. SRID=4326.
+// Options without a value are flags, e.g. WITH SYSTEM VERSIONING of a mariadb table.
+type Option struct {
+	// Name is the upper case option name, e.g. ENGINE, CHARSET.
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// setOption sets the option with name in options, appending it if not present.
+func setOption(options []*Option, name, value string) []*Option {
+	for _, o := range options {
+		if strings.EqualFold(o.Name, name) {
+			o.Value = value
+			return options
+		}
+	}
+	return append(options, &Option{Name: name, Value: value})
+}
+
+// option returns the value of the option with name in options.
+func option(options []*Option, name string) (string, bool) {
+	for _, o := range options {
+		if strings.EqualFold(o.Name, name) {
+			return o.Value, true
+		}
+	}
+	return "", false
+}
+
+// Clone returns a deep copy of t.
+func (t *Table) Clone() *Table {
+	c := *t
+	c.Columns = make([]*Column, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		c.Columns = append(c.Columns, col.Clone())
+	}
+	c.Indexes = make([]*Index, 0, len(t.Indexes))
+	for _, idx := range t.Indexes {
+		c.Indexes = append(c.Indexes, idx.Clone())
+	}
+	c.Constraints = make([]*Constraint, 0, len(t.Constraints))
+	for _, constraint := range t.Constraints {
+		cc := *constraint
+		cc.Columns = append([]string(nil), constraint.Columns...)
+		cc.RefColumns = append([]string(nil), constraint.RefColumns...)
+		c.Constraints = append(c.Constraints, &cc)
+	}
+	c.Options = cloneOptions(t.Options)
+	return &c
+}
+
+// Clone returns a deep copy of c.
+func (c *Column) Clone() *Column {
+	cc := *c
+	if c.Default != nil {
+		d := *c.Default
+		cc.Default = &d
+	}
+	cc.Type.Length = cloneInt(c.Type.Length)
+	cc.Type.Scale = cloneInt(c.Type.Scale)
+	cc.Type.Values = append([]string(nil), c.Type.Values...)
+	cc.Options = cloneOptions(c.Options)
+	return &cc
+}
+
+// Clone returns a deep copy of idx.
+func (idx *Index) Clone() *Index {
+	c := *idx
+	c.Columns = make([]*IndexColumn, 0, len(idx.Columns))
+	for _, col := range idx.Columns {
+		cc := *col
+		cc.Length = cloneInt(col.Length)
+		c.Columns = append(c.Columns, &cc)
+	}
+	return &c
+}
+
+func cloneInt(i *int) *int {
+	if i == nil {
+		return nil
+	}
+	v := *i
+	return &v
+}
+
+func cloneOptions(options []*Option) []*Option {
+	if options == nil {
+		return nil
+	}
+	result := make([]*Option, 0, len(options))
+	for _, o := range options {
+		oc := *o
+		result = append(result, &oc)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDropColumn(t *testing.T) {
+	table := &Table{
+		Name: "orders",
+		Columns: []*Column{
+			{Name: "id"}, {Name: "user_id"}, {Name: "total"}, {Name: "discount"},
+		},
+		Indexes: []*Index{
+			{Kind: IndexPrimary, Columns: []*IndexColumn{{Name: "id"}}},
+			{Name: "idx_user", Kind: IndexNormal, Columns: []*IndexColumn{{Name: "user_id"}}},
+			{Name: "idx_user_total", Kind: IndexNormal, Columns: []*IndexColumn{{Name: "user_id"}, {Name: "total"}}},
+		},
+		Constraints: []*Constraint{
+			{Name: "fk_user", Type: ConstraintForeignKey, Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+			{Name: "chk_user", Type: ConstraintCheck, Columns: []string{"user_id"}, Check: "user_id > 0"},
+			{Name: "chk_total", Type: ConstraintCheck, Check: "`total` >= 0"},
+			{Name: "chk_discount", Type: ConstraintCheck, Check: "discount <= TOTAL"},
+			{Name: "chk_literal", Type: ConstraintCheck, Check: "discount <> 'user_id'"},
+		},
+	}
+
+	if c := table.DropColumn("USER_ID"); c == nil || c.Name != "user_id" {
+		t.Fatalf("DropColumn = %v, want user_id", c)
+	}
+	table.DropColumn("total")
+
+	var columns, indexes, constraints []string
+	for _, c := range table.Columns {
+		columns = append(columns, c.Name)
+	}
+	for _, idx := range table.Indexes {
+		indexes = append(indexes, idx.Kind+" "+idx.Name)
+	}
+	for _, c := range table.Constraints {
+		constraints = append(constraints, c.Name)
+	}
+	if want := []string{"id", "discount"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("columns = %v, want %v", columns, want)
+	}
+	if want := []string{"PRIMARY "}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("indexes = %v, want %v", indexes, want)
+	}
+	if want := []string{"chk_literal"}; !reflect.DeepEqual(constraints, want) {
+		t.Errorf("constraints = %v, want %v", constraints, want)
+	}
+	if table.DropColumn("missing") != nil {
+		t.Errorf("DropColumn of a missing column is not nil")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/er1c-zh/sql-to-gorm/ddl"
+	"github.com/er1c-zh/sql-to-gorm/reverse"
+)
+
+const sqlHeader = "-- Code generated by sql-to-gorm. DO NOT EDIT."
+
+// structToSQL writes CREATE TABLE statements of the gorm models in go packages.
+func structToSQL(args []string) {
+	flags := flag.NewFlagSet("struct-to-sql", flag.ExitOnError)
+	out := flags.String("out", "", "write the statements to this file instead of stdout")
+	force := flags.Bool("force", false, "overwrite a file which is not generated by sql-to-gorm")
+	dir := flags.String("dir", "", "directory to load the packages in")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(),
+			"Usage: %s struct-to-sql [flags] [package]...\n"+
+				"Packages are go package patterns, ./... by default.\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	option := reverse.Option{
+		Dir: *dir,
+		Warnf: func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, "[WARN] "+format+"\n", args...)
+		},
+	}
+	s, err := reverse.Load(option, patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load packages fail: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(sqlHeader + "\n\n")
+	if err = ddl.Write(buf, s); err == nil {
+		if *out != "" {
+			err = WriteFile(*out, buf.String(), *force)
+		} else {
+			_, err = os.Stdout.Write(buf.Bytes())
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "write fail: %s\n", err.Error())
+		os.Exit(1)
+	}
+}